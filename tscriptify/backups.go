@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify"
+)
+
+const backupsUsage = `Usage:
+  tscriptify backups list [-backup dir] <target.ts>
+  tscriptify backups restore [-backup dir] [-from backup] <target.ts>`
+
+// backups runs the `backups list` and `backups restore` subcommands.
+func backups(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", backupsUsage)
+	}
+
+	fs := flag.NewFlagSet("backups "+args[0], flag.ExitOnError)
+	backupDir := fs.String("backup", typescriptify.DefaultBackupDir, "Directory where backup files are saved")
+	from := fs.String("from", "", "Backup file to restore (default is the last backup)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%s", backupsUsage)
+	}
+	target := fs.Arg(0)
+
+	backups, err := typescriptify.ListBackups(*backupDir, target)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		for _, backup := range backups {
+			fmt.Printf("%s  %s\n", backup.Time.Format("2006-01-02 15:04:05"), backup.Path)
+		}
+		return nil
+	case "restore":
+		if len(backups) == 0 {
+			return fmt.Errorf("no backups of %s in %s", target, *backupDir)
+		}
+		backup := backups[0]
+		if *from != "" {
+			found := false
+			for _, b := range backups {
+				if filepath.Clean(b.Path) == filepath.Clean(*from) || filepath.Base(b.Path) == *from {
+					backup, found = b, true
+				}
+			}
+			if !found {
+				return fmt.Errorf("%s isn't a backup of %s", *from, target)
+			}
+		}
+		if err := typescriptify.RestoreBackup(backup, target); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Restored", target, "from", backup.Path)
+		return nil
+	}
+	return fmt.Errorf("%s", backupsUsage)
+}
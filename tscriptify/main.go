@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"os/exec"
 	"strings"
 	"text/template"
+	"time"
+
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify"
 )
 
 type arrayImports []string
@@ -27,9 +32,11 @@ const TEMPLATE = `package main
 
 import (
 	"fmt"
-
-	m "{{ .ModelsPackage }}"
-	"github.com/tkrajina/typescriptify-golang-structs/typescriptify"
+{{ if .ManagedTypes }}	"reflect"
+{{ end }}
+{{ range .Packages }}	{{ .Alias }} "{{ .ImportPath }}"
+{{ end }}{{ range .ManagedTypes }}	{{ .Alias }} "{{ .ImportPath }}"
+{{ end }}	"github.com/tkrajina/typescriptify-golang-structs/typescriptify"
 )
 
 func main() {
@@ -37,132 +44,378 @@ func main() {
 	t.CreateInterface = {{ .Interface }}
 {{ range $key, $value := .InitParams }}	t.{{ $key }}={{ $value }}
 {{ end }}
-{{ range .Structs }}	t.Add({{ . }}{})
+{{ range .ManagedTypes }}	t.ManageType({{ .Expression }})
+{{ end }}{{ range .Enums }}	t.AddEnum({{ . }})
+{{ end }}{{ range .ProtoEnums }}	t.AddProtoEnum({{ . }})
+{{ end }}{{ range .Structs }}	t.Add({{ . }})
+{{ end }}{{ range .CodeBefore }}	t.WithCustomCodeBefore({{ . }})
+{{ end }}{{ range .CodeAfter }}	t.WithCustomCodeAfter({{ . }})
 {{ end }}
 {{ range .CustomImports }}	t.AddImport("{{ . }}")
 {{ end }}
-	err := t.ConvertToFile("{{ .TargetFile }}")
+	changed, err := t.ConvertToFileIfChanged("{{ .TargetFile }}")
 	if err != nil {
 		panic(err.Error())
 	}
-	fmt.Println("OK")
+	if changed {
+		fmt.Println("OK")
+	} else {
+		fmt.Println("OK (unchanged)")
+	}
 }`
 
 type Params struct {
-	ModelsPackage string
-	TargetFile    string
-	Structs       []string
-	InitParams    map[string]interface{}
-	CustomImports arrayImports
-	Interface     bool
-	Verbose       bool
+	ModelsPackages arrayFlags
+	Packages       []ModelsPackage
+	TargetFile     string
+	Structs        []string
+	Excludes       arrayFlags
+	All            bool
+	InitParams     map[string]interface{}
+	CustomImports  arrayImports
+	EnumVars       arrayFlags
+	Enums          []string
+	Protobuf       bool
+	ProtoEnums     []string
+	TypeMappings   arrayFlags
+	ManagedTypes   []managedType
+	CodeBeforeFile arrayFlags
+	CodeAfterFile  arrayFlags
+	CodeBefore     []string
+	CodeAfter      []string
+	Interface      bool
+	Verbose        bool
+	Watch          bool
+	Debounce       time.Duration
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backups" {
+		if err := backups(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	var p Params
-	var backupDir string
-	flag.StringVar(&p.ModelsPackage, "package", "", "Path of the package with models")
+	var backupDir, prefix, suffix, indent, jsonTag, output string
+	var dontExport, constructor, classValidator bool
+	var backupKeep int
+	var backupMaxAge time.Duration
+	flag.Var(&p.ModelsPackages, "package", "Path of the package with models (or a pattern like ./models/...), repeat this option for each package (default is the current directory)")
 	flag.StringVar(&p.TargetFile, "target", "", "Target typescript file")
-	flag.StringVar(&backupDir, "backup", "", "Directory where backup files are saved")
+	flag.StringVar(&output, "output", "", "Generated code: ts, d.ts (declarations) or js (ES module with JSDoc, and a .d.ts file), default by the target file extension")
+	flag.StringVar(&backupDir, "backup", typescriptify.DefaultBackupDir, "Directory where backup files are saved (empty for no backups)")
+	flag.IntVar(&backupKeep, "backup-keep", 10, "Number of backups kept (0 for all)")
+	flag.DurationVar(&backupMaxAge, "backup-max-age", 0, "Remove backups older than this, i.e. 168h (0 for no limit)")
 	flag.BoolVar(&p.Interface, "interface", false, "Create interfaces (not classes)")
+	flag.StringVar(&prefix, "prefix", "", "Prefix for typescript type names")
+	flag.StringVar(&suffix, "suffix", "", "Suffix for typescript type names")
+	flag.StringVar(&indent, "indent", "", "Indentation, i.e. \\t or two spaces (default four spaces)")
+	flag.BoolVar(&dontExport, "dont-export", false, "Don't export the typescript types")
+	flag.BoolVar(&constructor, "constructor", true, "Create constructors for classes")
+	flag.BoolVar(&classValidator, "class-validator", false, "Add class-validator and class-transformer decorators to class properties")
+	flag.StringVar(&jsonTag, "json-tag", "", "Read field names from this tag instead of json")
+	flag.Var(&p.CodeBeforeFile, "code-before", "File with custom typescript code added before the models, repeat this option for each file")
+	flag.Var(&p.CodeAfterFile, "code-after", "File with custom typescript code added after the models, repeat this option for each file")
+	flag.Var(&p.TypeMappings, "type", "Typescript type (and transformation) for a Golang type, i.e. time.Time=Date:new Date(__VALUE__), repeat this option for each type")
+	flag.Var(&p.EnumVars, "enum", "Variable with all values of an enum, i.e. AllWeekdays, repeat this option for each enum")
 	flag.Var(&p.CustomImports, "import", "Typescript import for your custom type, repeat this option for each import needed")
+	flag.BoolVar(&p.Protobuf, "protobuf", false, "Convert structs generated by protoc-gen-go as serialized with protojson (enums are added from the _name maps)")
+	flag.BoolVar(&p.All, "all", false, "Convert all exported structs in the packages")
+	flag.Var(&p.Excludes, "exclude", "Don't convert structs matching this name or pattern, repeat this option for each pattern")
 	flag.BoolVar(&p.Verbose, "verbose", false, "Verbose logs")
+	flag.BoolVar(&p.Watch, "watch", false, "Watch the models package (and Golang files given as arguments) and regenerate on changes")
+	flag.DurationVar(&p.Debounce, "debounce", 300*time.Millisecond, "Wait for this long without changes before regenerating (with -watch)")
 	flag.Parse()
 
-	structs := []string{}
-	for _, structOrGoFile := range flag.Args() {
-		if strings.HasSuffix(structOrGoFile, ".go") {
-			fmt.Println("Parsing:", structOrGoFile)
-			fileStructs, err := GetGolangFileStructs(structOrGoFile)
-			if err != nil {
-				panic(fmt.Sprintf("Error loading/parsing golang file %s: %s", structOrGoFile, err.Error()))
-			}
-			structs = append(structs, fileStructs...)
-		} else {
-			structs = append(structs, structOrGoFile)
-		}
-	}
-
-	if len(p.ModelsPackage) == 0 {
-		fmt.Fprintln(os.Stderr, "No package given")
-		os.Exit(1)
+	if len(p.ModelsPackages) == 0 {
+		// The current directory, i.e. with `//go:generate`:
+		p.ModelsPackages = arrayFlags{"."}
 	}
 	if len(p.TargetFile) == 0 {
 		fmt.Fprintln(os.Stderr, "No target file")
 		os.Exit(1)
 	}
 
-	t := template.Must(template.New("").Parse(TEMPLATE))
+	p.InitParams = map[string]interface{}{
+		"BackupDir":         fmt.Sprintf(`"%s"`, backupDir),
+		"BackupKeep":        backupKeep,
+		"BackupMaxAge":      fmt.Sprintf("%d", backupMaxAge),
+		"CreateConstructor": constructor,
+		"DontExport":        dontExport,
+	}
+	if prefix != "" {
+		p.InitParams["Prefix"] = fmt.Sprintf("%q", prefix)
+	}
+	if suffix != "" {
+		p.InitParams["Suffix"] = fmt.Sprintf("%q", suffix)
+	}
+	if indent != "" {
+		p.InitParams["Indent"] = fmt.Sprintf("%q", strings.ReplaceAll(indent, `\t`, "\t"))
+	}
+	if jsonTag != "" {
+		p.InitParams["CustomJsonTag"] = fmt.Sprintf("%q", jsonTag)
+	}
+	if p.Protobuf {
+		p.InitParams["Protobuf"] = true
+	}
+	if classValidator {
+		p.InitParams["ClassValidator"] = true
+	}
+	outputExpr, err := outputExpression(output, p.TargetFile)
+	handleErr(err)
+	if outputExpr != "typescriptify.TypeScriptOutput" {
+		p.InitParams["Output"] = outputExpr
+	}
+	for n, mapping := range p.TypeMappings {
+		typ, err := parseManagedType(mapping, n)
+		handleErr(err)
+		p.ManagedTypes = append(p.ManagedTypes, typ)
+	}
 
 	f, err := os.CreateTemp(os.TempDir(), "typescriptify_*.go")
 	handleErr(err)
-	defer f.Close()
+	f.Close()
+	defer os.Remove(f.Name())
 
-	structsArr := make([]string, 0)
-	for _, str := range structs {
-		str = strings.TrimSpace(str)
-		if len(str) > 0 {
-			structsArr = append(structsArr, "m."+str)
+	if p.Watch {
+		// Not with handleErr, which exits before the deferred cleanup:
+		if err := watch(p, flag.Args(), f.Name()); err != nil {
+			os.Remove(f.Name())
+			handleErr(err)
 		}
+		return
 	}
+	handleErr(generate(p, flag.Args(), f.Name()))
+}
 
-	p.Structs = structsArr
-	p.InitParams = map[string]interface{}{
-		"BackupDir": fmt.Sprintf(`"%s"`, backupDir),
+// generate writes the conversion program to programFile and runs it. Packages and Golang files in args are parsed for
+// structs on every call (they may have changed in watch mode).
+func generate(p Params, args []string, programFile string) error {
+	pkgs, err := loadPackages(p.ModelsPackages)
+	if err != nil {
+		return err
+	}
+	selected, err := selectStructs(pkgs, args, p.All, p.Excludes)
+	if err != nil {
+		return err
+	}
+	used := make([]bool, len(pkgs))
+	for n, types := range selected {
+		for _, typ := range types {
+			p.Structs = append(p.Structs, typ.expression(pkgs[n].Alias))
+			used[n] = true
+		}
+	}
+	for _, enumVar := range p.EnumVars {
+		enum, n, err := enumExpression(pkgs, enumVar)
+		if err != nil {
+			return err
+		}
+		p.Enums = append(p.Enums, enum)
+		used[n] = true
+	}
+	// Only used packages, unused imports don't compile:
+	for n, pkg := range pkgs {
+		if used[n] {
+			p.Packages = append(p.Packages, pkg)
+			if p.Protobuf {
+				p.ProtoEnums = append(p.ProtoEnums, protoEnumExpressions(pkg)...)
+			}
+		}
+	}
+	if p.CodeBefore, err = customCodeFiles(p.CodeBeforeFile); err != nil {
+		return err
+	}
+	if p.CodeAfter, err = customCodeFiles(p.CodeAfterFile); err != nil {
+		return err
+	}
+
+	var code bytes.Buffer
+	t := template.Must(template.New("").Parse(TEMPLATE))
+	if err := t.Execute(&code, p); err != nil {
+		return err
+	}
+
+	// Rewritten only if changed, so that the build cache can be reused:
+	if existing, err := os.ReadFile(programFile); err != nil || !bytes.Equal(existing, code.Bytes()) {
+		if err := os.WriteFile(programFile, code.Bytes(), 0600); err != nil {
+			return err
+		}
 	}
-	err = t.Execute(f, p)
-	handleErr(err)
 
 	if p.Verbose {
-		byts, err := os.ReadFile(f.Name())
-		handleErr(err)
-		fmt.Printf("\nCompiling generated code (%s):\n%s\n----------------------------------------------------------------------------------------------------\n", f.Name(), string(byts))
+		fmt.Printf("\nCompiling generated code (%s):\n%s\n----------------------------------------------------------------------------------------------------\n", programFile, code.String())
 	}
 
-	cmd := exec.Command("go", "run", f.Name())
+	cmd := exec.Command("go", "run", programFile)
 	fmt.Println(strings.Join(cmd.Args, " "))
 	output, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Println(string(output))
-		handleErr(err)
-	}
 	fmt.Println(string(output))
+	return err
+}
+
+const directive = "//tscriptify"
+
+// golangType is a type declared in a Golang file, with options from the `//tscriptify:...` directive comment.
+type golangType struct {
+	Name      string
+	Annotated bool // Has a `//tscriptify` comment
+	TSName    string
+	Interface bool
+	Included  bool // Not declared as a struct, but added with `//tscriptify:include` (i.e. `type Admin User`)
 }
 
+// expression returns the Golang expression passed to `TypeScriptify.Add()` in the conversion program.
+func (gt golangType) expression(alias string) string {
+	expr := alias + "." + gt.Name + "{}"
+	if gt.Included {
+		// Composite literals compile only for (known) structs:
+		expr = "*new(" + alias + "." + gt.Name + ")"
+	}
+	if gt.TSName == "" && !gt.Interface {
+		return expr
+	}
+	expr = "typescriptify.NewStruct(" + expr + ")"
+	if gt.TSName != "" {
+		expr += fmt.Sprintf(".WithTSName(%q)", gt.TSName)
+	}
+	if gt.Interface {
+		expr += ".WithInterface(true)"
+	}
+	return expr
+}
+
+// GetGolangFileStructs returns the exported, non-generic structs declared (at the top level) in a Golang file.
+//
+// Types with a `//tscriptify:ignore` comment are skipped, and other exported types (i.e. `type Admin User`) can be
+// added with `//tscriptify:include`.
 func GetGolangFileStructs(filename string) ([]string, error) {
+	types, err := getGolangFileTypes(filename)
+	if err != nil {
+		return nil, err
+	}
+	var structs []string
+	for _, typ := range types {
+		structs = append(structs, typ.Name)
+	}
+	return structs, nil
+}
+
+func getGolangFileTypes(filename string) ([]golangType, error) {
+	types, _, err := parseGolangFile(filename)
+	return types, err
+}
+
+// parseGolangFile returns the types, and the names of exported variables (i.e. enum values) declared in a Golang file.
+func parseGolangFile(filename string) ([]golangType, []string, error) {
 	fset := token.NewFileSet() // positions are relative to fset
 
-	f, err := parser.ParseFile(fset, filename, nil, 0)
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	v := &AVisitor{}
-	ast.Walk(v, f)
+	var types []golangType
+	var vars []string
+	for _, decl := range f.Decls {
+		genDecl, is := decl.(*ast.GenDecl)
+		if !is {
+			continue
+		}
+		if genDecl.Tok == token.VAR {
+			for _, spec := range genDecl.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					if name.IsExported() {
+						vars = append(vars, name.Name)
+					}
+				}
+			}
+			continue
+		}
+		if genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if !typeSpec.Name.IsExported() || typeSpec.TypeParams != nil {
+				continue
+			}
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+			typ := golangType{Name: typeSpec.Name.Name}
+			ignore, include, err := parseDirective(doc, &typ)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", fset.Position(typeSpec.Pos()), err)
+			}
+			_, isStruct := typeSpec.Type.(*ast.StructType)
+			if ignore || !(isStruct || include) {
+				continue
+			}
+			if !isStruct {
+				if !maybeStruct(typeSpec.Type) {
+					return nil, nil, fmt.Errorf("%s: %s can't be included, only structs can be converted", fset.Position(typeSpec.Pos()), typ.Name)
+				}
+				typ.Included = true
+			}
+			types = append(types, typ)
+		}
+	}
 
-	return v.structs, nil
+	return types, vars, nil
 }
 
-type AVisitor struct {
-	structNameCandidate string
-	structs             []string
+// maybeStruct returns true if a type expression can be a struct declared elsewhere (i.e. `User`, `models.User` or
+// `Page[User]`), false for builtin types, slices, maps, pointers, ...
+func maybeStruct(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return types.Universe.Lookup(e.Name) == nil
+	case *ast.SelectorExpr:
+		return true
+	case *ast.IndexExpr:
+		return maybeStruct(e.X)
+	case *ast.IndexListExpr:
+		return maybeStruct(e.X)
+	case *ast.ParenExpr:
+		return maybeStruct(e.X)
+	}
+	return false
 }
 
-func (v *AVisitor) Visit(node ast.Node) ast.Visitor {
-	if node != nil {
-		switch t := node.(type) {
-		case *ast.Ident:
-			v.structNameCandidate = t.Name
-		case *ast.StructType:
-			if len(v.structNameCandidate) > 0 {
-				v.structs = append(v.structs, v.structNameCandidate)
-				v.structNameCandidate = ""
+// parseDirective parses `//tscriptify` and `//tscriptify:name=Foo,interface` (or `ignore`, `include`) comments.
+func parseDirective(doc *ast.CommentGroup, typ *golangType) (ignore, include bool, err error) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		if text != directive && !strings.HasPrefix(text, directive+":") {
+			continue
+		}
+		typ.Annotated = true
+		for _, opt := range strings.Split(strings.TrimPrefix(text, directive+":"), ",") {
+			opt = strings.TrimSpace(opt)
+			switch {
+			case opt == directive || opt == "":
+			case opt == "ignore":
+				ignore = true
+			case opt == "include":
+				include = true
+			case opt == "interface":
+				typ.Interface = true
+			case strings.HasPrefix(opt, "name="):
+				typ.TSName = strings.TrimPrefix(opt, "name=")
+			default:
+				return false, false, fmt.Errorf("invalid tscriptify option %s", opt)
 			}
-		default:
-			v.structNameCandidate = ""
 		}
 	}
-	return v
+	return
 }
 
 func handleErr(err error) {
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testModels = `package models
+
+type Person struct {
+	Name    string
+	Address struct {
+		Street string
+	}
+}
+
+type address struct {
+	City string
+}
+
+type Page[T any] struct {
+	Items []T
+}
+
+//tscriptify:ignore
+type Internal struct {
+	Secret string
+}
+
+type (
+	Circle struct{ Radius float64 }
+	//tscriptify:include
+	Admin Person
+	Role  string
+)
+
+func init() {
+	type Local struct{ X int }
+	_ = Local{}
+}
+`
+
+func TestGetGolangFileStructs(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "models.go")
+	assert.Nil(t, os.WriteFile(filename, []byte(testModels), 0644))
+
+	structs, err := GetGolangFileStructs(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Person", "Circle", "Admin"}, structs)
+}
+
+func TestIncludedTypes(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "models.go")
+	assert.Nil(t, os.WriteFile(filename, []byte(testModels), 0644))
+
+	types, err := getGolangFileTypes(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, golangType{Name: "Admin", Annotated: true, Included: true}, types[2])
+	assert.Equal(t, "*new(m0.Admin)", types[2].expression("m0"))
+	assert.Equal(t, `typescriptify.NewStruct(*new(m0.Admin)).WithInterface(true)`, golangType{Name: "Admin", Included: true, Interface: true}.expression("m0"))
+
+	for _, decl := range []string{"Role string", "IDs []int", "Ref *Person", "Labels map[string]string"} {
+		assert.Nil(t, os.WriteFile(filename, []byte("package models\n\n//tscriptify:include\ntype "+decl+"\n"), 0644))
+		_, err = getGolangFileTypes(filename)
+		assert.NotNil(t, err, decl)
+	}
+	for _, decl := range []string{"Admin Person", "Time time.Time", "Users Page[Person]"} {
+		assert.Nil(t, os.WriteFile(filename, []byte("package models\n\n//tscriptify:include\ntype "+decl+"\n"), 0644))
+		types, err = getGolangFileTypes(filename)
+		assert.Nil(t, err, decl)
+		assert.Len(t, types, 1, decl)
+	}
+}
+
+func TestGetGolangFileTypesWithDirectives(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "models.go")
+	assert.Nil(t, os.WriteFile(filename, []byte(`package models
+
+//tscriptify
+type User struct{}
+
+// OrderItem is an item.
+//tscriptify:name=Item,interface
+type OrderItem struct{}
+
+type Order struct{}
+`), 0644))
+
+	types, err := getGolangFileTypes(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, []golangType{
+		{Name: "User", Annotated: true},
+		{Name: "OrderItem", Annotated: true, TSName: "Item", Interface: true},
+		{Name: "Order"},
+	}, types)
+	assert.Equal(t, "m0.User{}", types[0].expression("m0"))
+	assert.Equal(t, `typescriptify.NewStruct(m0.OrderItem{}).WithTSName("Item").WithInterface(true)`, types[1].expression("m0"))
+
+	selected, err := selectStructs([]ModelsPackage{{Alias: "m0", Types: types}}, nil, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]golangType{types[:2]}, selected)
+
+	assert.Nil(t, os.WriteFile(filename, []byte("package models\n\n//tscriptify:nme=X\ntype User struct{}\n"), 0644))
+	_, err = getGolangFileTypes(filename)
+	assert.NotNil(t, err)
+}
+
+func TestParseManagedType(t *testing.T) {
+	t.Parallel()
+
+	typ, err := parseManagedType("time.Time=Date:new Date(__VALUE__)", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, managedType{ImportPath: "time", Alias: "t0", Name: "Time", TSType: "Date", TSTransform: "new Date(__VALUE__)"}, typ)
+	assert.Equal(t, `reflect.TypeOf((*t0.Time)(nil)).Elem(), typescriptify.TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"}`, typ.Expression())
+
+	typ, err = parseManagedType("github.com/shopspring/decimal.Decimal=string", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, managedType{ImportPath: "github.com/shopspring/decimal", Alias: "t1", Name: "Decimal", TSType: "string"}, typ)
+
+	for _, invalid := range []string{"time.Time", "Time=Date", "time.=Date", "time.Time="} {
+		_, err = parseManagedType(invalid, 0)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestProtoEnumExpressions(t *testing.T) {
+	t.Parallel()
+
+	pkg := ModelsPackage{Alias: "m1", Vars: []string{"Status_name", "Status_value", "Person_PhoneType_name", "Person_PhoneType_value", "Default_name"}}
+	assert.Equal(t, []string{"m1.Status(0), m1.Status_name", "m1.Person_PhoneType(0), m1.Person_PhoneType_name"}, protoEnumExpressions(pkg))
+}
+
+func TestOutputExpression(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ output, target, expected string }{
+		{"", "models.ts", "typescriptify.TypeScriptOutput"},
+		{"", "models.d.ts", "typescriptify.DeclarationOutput"},
+		{"", "models.js", "typescriptify.JavaScriptOutput"},
+		{"", "models.mjs", "typescriptify.JavaScriptOutput"},
+		{"d.ts", "models.ts", "typescriptify.DeclarationOutput"},
+	} {
+		expr, err := outputExpression(tc.output, tc.target)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, expr, tc.target)
+	}
+	_, err := outputExpression("tsx", "models.tsx")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// managedType is a `-type` mapping, i.e. `time.Time=Date:new Date(__VALUE__)`.
+type managedType struct {
+	ImportPath  string
+	Alias       string
+	Name        string
+	TSType      string
+	TSTransform string
+}
+
+// parseManagedType parses `import/path.Type=TSType[:TSTransform]`.
+func parseManagedType(s string, n int) (managedType, error) {
+	goType, ts, found := strings.Cut(s, "=")
+	dot := strings.LastIndex(goType, ".")
+	if !found || dot <= 0 || dot == len(goType)-1 {
+		return managedType{}, fmt.Errorf("invalid type mapping %s, should be i.e. time.Time=Date:new Date(__VALUE__)", s)
+	}
+	typ := managedType{
+		ImportPath: goType[:dot],
+		Alias:      fmt.Sprintf("t%d", n),
+		Name:       goType[dot+1:],
+	}
+	typ.TSType, typ.TSTransform, _ = strings.Cut(ts, ":")
+	if typ.TSType == "" {
+		return managedType{}, fmt.Errorf("invalid type mapping %s, no typescript type", s)
+	}
+	return typ, nil
+}
+
+// Expression returns the arguments of `TypeScriptify.ManageType()` in the conversion program.
+func (mt managedType) Expression() string {
+	opts := fmt.Sprintf("TSType: %q", mt.TSType)
+	if mt.TSTransform != "" {
+		opts += fmt.Sprintf(", TSTransform: %q", mt.TSTransform)
+	}
+	return fmt.Sprintf("reflect.TypeOf((*%s.%s)(nil)).Elem(), typescriptify.TypeOptions{%s}", mt.Alias, mt.Name, opts)
+}
+
+// customCodeFiles returns the (quoted) contents of custom code files.
+func customCodeFiles(files []string) ([]string, error) {
+	var result []string
+	for _, file := range files {
+		byts, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, fmt.Sprintf("%q", string(byts)))
+	}
+	return result, nil
+}
+
+// enumExpression returns the qualified enum values variable (i.e. `m0.AllWeekdays`), and the index of its package.
+func enumExpression(pkgs []ModelsPackage, name string) (string, int, error) {
+	for n, pkg := range pkgs {
+		for _, v := range pkg.Vars {
+			if v == name {
+				return pkg.Alias + "." + name, n, nil
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("enum values variable %s not found", name)
+}
+
+// protoEnumExpressions returns the AddProtoEnum arguments (i.e. `m0.Status(0), m0.Status_name`) for the protobuf enums
+// of a package, found by the generated `_name` and `_value` maps.
+func protoEnumExpressions(pkg ModelsPackage) []string {
+	vars := map[string]bool{}
+	for _, v := range pkg.Vars {
+		vars[v] = true
+	}
+	var result []string
+	for _, v := range pkg.Vars {
+		if typ := strings.TrimSuffix(v, "_name"); typ != v && typ != "" && vars[typ+"_value"] {
+			result = append(result, fmt.Sprintf("%s.%s(0), %s.%s", pkg.Alias, typ, pkg.Alias, v))
+		}
+	}
+	return result
+}
+
+// outputExpression returns the typescriptify.Output constant for the `-output` option, inferred from the target file
+// extension if empty.
+func outputExpression(output, targetFile string) (string, error) {
+	if output == "" {
+		switch {
+		case strings.HasSuffix(targetFile, ".d.ts"), strings.HasSuffix(targetFile, ".d.mts"):
+			output = "d.ts"
+		case strings.HasSuffix(targetFile, ".js"), strings.HasSuffix(targetFile, ".mjs"):
+			output = "js"
+		default:
+			output = "ts"
+		}
+	}
+	switch output {
+	case "ts":
+		return "typescriptify.TypeScriptOutput", nil
+	case "d.ts":
+		return "typescriptify.DeclarationOutput", nil
+	case "js":
+		return "typescriptify.JavaScriptOutput", nil
+	}
+	return "", fmt.Errorf("invalid output %s, should be ts, d.ts or js", output)
+}
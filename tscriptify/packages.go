@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type arrayFlags []string
+
+func (i *arrayFlags) String() string {
+	return strings.Join(*i, ",")
+}
+
+func (i *arrayFlags) Set(value string) error {
+	*i = append(*i, value)
+	return nil
+}
+
+// ModelsPackage is a package with models, Types are all its exported structs (and included types), and Vars its exported
+// variables.
+type ModelsPackage struct {
+	Name       string
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Alias      string
+	Types      []golangType
+	Vars       []string
+}
+
+// loadPackages resolves package paths and patterns (i.e. `./models/...`) with `go list`.
+func loadPackages(patterns []string) ([]ModelsPackage, error) {
+	cmd := exec.Command("go", append([]string{"list", "-json"}, patterns...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, is := err.(*exec.ExitError); is {
+			return nil, fmt.Errorf("cannot list packages %s: %s", strings.Join(patterns, " "), string(exitErr.Stderr))
+		}
+		return nil, err
+	}
+
+	var pkgs []ModelsPackage
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+	for decoder.More() {
+		var pkg ModelsPackage
+		if err := decoder.Decode(&pkg); err != nil {
+			return nil, err
+		}
+		if pkg.Name == "main" { // Not importable
+			continue
+		}
+		pkg.Alias = fmt.Sprintf("m%d", len(pkgs))
+		for _, file := range pkg.GoFiles {
+			fileTypes, fileVars, err := parseGolangFile(filepath.Join(pkg.Dir, file))
+			if err != nil {
+				return nil, fmt.Errorf("error loading/parsing golang file %s: %w", file, err)
+			}
+			pkg.Types = append(pkg.Types, fileTypes...)
+			pkg.Vars = append(pkg.Vars, fileVars...)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// isNamePattern returns true for glob (`*Response`) and regexp (`/^Get.*Response$/`) struct name patterns.
+func isNamePattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[") || isRegexpPattern(pattern)
+}
+
+func isRegexpPattern(pattern string) bool {
+	return len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/")
+}
+
+// nameMatcher returns a function matching struct names with a struct name, glob or regexp (between slashes).
+func nameMatcher(pattern string) (func(string) bool, error) {
+	if isRegexpPattern(pattern) {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return func(name string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}, nil
+}
+
+// packageOfFile returns the index of the package containing a Golang file.
+func packageOfFile(pkgs []ModelsPackage, file string) (int, error) {
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return 0, err
+	}
+	for n, pkg := range pkgs {
+		if pkg.Dir == dir {
+			return n, nil
+		}
+	}
+	if len(pkgs) == 1 {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%s isn't in any of the packages", file)
+}
+
+func findType(types []golangType, name string) (golangType, bool) {
+	for _, typ := range types {
+		if typ.Name == name {
+			return typ, true
+		}
+	}
+	return golangType{}, false
+}
+
+// selectStructs returns the structs (for every package) selected by the arguments (struct names, patterns or Golang
+// files), all structs if all is true, without the excluded ones.
+//
+// Without arguments, the structs with `//tscriptify` comments are selected. If there are none, and the tool is
+// started by `go generate`, then the structs in the file with the `//go:generate` comment.
+func selectStructs(pkgs []ModelsPackage, args []string, all bool, excludes []string) ([][]golangType, error) {
+	selected := make([][]golangType, len(pkgs))
+	add := func(n int, types ...golangType) {
+		for _, typ := range types {
+			if _, found := findType(selected[n], typ.Name); !found {
+				selected[n] = append(selected[n], typ)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		switch {
+		case arg == "":
+		case strings.HasSuffix(arg, ".go"):
+			fileTypes, err := getGolangFileTypes(arg)
+			if err != nil {
+				return nil, fmt.Errorf("error loading/parsing golang file %s: %w", arg, err)
+			}
+			n, err := packageOfFile(pkgs, arg)
+			if err != nil {
+				return nil, err
+			}
+			add(n, fileTypes...)
+		case isNamePattern(arg):
+			matcher, err := nameMatcher(arg)
+			if err != nil {
+				return nil, err
+			}
+			for n, pkg := range pkgs {
+				for _, typ := range pkg.Types {
+					if matcher(typ.Name) {
+						add(n, typ)
+					}
+				}
+			}
+		default:
+			found := false
+			for n, pkg := range pkgs {
+				if typ, foundInPkg := findType(pkg.Types, arg); foundInPkg {
+					add(n, typ)
+					found = true
+				}
+			}
+			if !found {
+				if len(pkgs) != 1 {
+					return nil, fmt.Errorf("struct %s not found", arg)
+				}
+				add(0, golangType{Name: arg})
+			}
+		}
+	}
+	if all {
+		for n, pkg := range pkgs {
+			add(n, pkg.Types...)
+		}
+	}
+	if len(args) == 0 && !all {
+		for n, pkg := range pkgs {
+			for _, typ := range pkg.Types {
+				if typ.Annotated {
+					add(n, typ)
+				}
+			}
+		}
+		if goFile := os.Getenv("GOFILE"); goFile != "" && len(pkgs) == 1 && len(selected[0]) == 0 {
+			fileTypes, err := getGolangFileTypes(goFile)
+			if err != nil {
+				return nil, fmt.Errorf("error loading/parsing golang file %s: %w", goFile, err)
+			}
+			add(0, fileTypes...)
+		}
+	}
+
+	var excluders []func(string) bool
+	for _, exclude := range excludes {
+		matcher, err := nameMatcher(exclude)
+		if err != nil {
+			return nil, err
+		}
+		excluders = append(excluders, matcher)
+	}
+
+	result := make([][]golangType, len(pkgs))
+	for n, types := range selected {
+	types:
+		for _, typ := range types {
+			for _, excluded := range excluders {
+				if excluded(typ.Name) {
+					continue types
+				}
+			}
+			result[n] = append(result[n], typ)
+		}
+	}
+	return result, nil
+}
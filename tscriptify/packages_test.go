@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectStructs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "models.go")
+	assert.Nil(t, os.WriteFile(filename, []byte(testModels), 0644))
+	absDir, err := filepath.Abs(dir)
+	assert.Nil(t, err)
+	otherFilename := filepath.Join(t.TempDir(), "other.go")
+	assert.Nil(t, os.WriteFile(otherFilename, []byte(testModels), 0644))
+
+	models := ModelsPackage{Alias: "m0", Dir: absDir, Types: []golangType{
+		{Name: "User"}, {Name: "GetUserResponse"}, {Name: "ListUsersResponse"}, {Name: "CreateUserRequest", Annotated: true},
+	}}
+	api := ModelsPackage{Alias: "m1", Dir: "/api", Types: []golangType{
+		{Name: "GetOrderResponse"}, {Name: "Order", Annotated: true}, {Name: "User"},
+	}}
+	two := []ModelsPackage{models, api}
+
+	for _, tc := range []struct {
+		name     string
+		pkgs     []ModelsPackage
+		args     []string
+		all      bool
+		excludes []string
+		expected [][]string
+	}{
+		{"names", two, []string{"User", "Order"}, false, nil, [][]string{{"User"}, {"User", "Order"}}},
+		{"glob", two, []string{"*Response"}, false, nil, [][]string{{"GetUserResponse", "ListUsersResponse"}, {"GetOrderResponse"}}},
+		{"regexp", two, []string{"/^Get.*Response$/"}, false, nil, [][]string{{"GetUserResponse"}, {"GetOrderResponse"}}},
+		{"all", two, nil, true, nil, [][]string{{"User", "GetUserResponse", "ListUsersResponse", "CreateUserRequest"}, {"GetOrderResponse", "Order", "User"}}},
+		{"exclude", two, nil, true, []string{"/Response$/", "Order"}, [][]string{{"User", "CreateUserRequest"}, {"User"}}},
+		{"annotated", two, nil, false, nil, [][]string{{"CreateUserRequest"}, {"Order"}}},
+		{"duplicates", two, []string{"User", "User", "*User"}, false, nil, [][]string{{"User"}, {"User"}}},
+		{"file", two, []string{filename}, false, []string{"Admin"}, [][]string{{"Person", "Circle"}, nil}},
+		{"unknown in one package", []ModelsPackage{models}, []string{"Generated"}, false, nil, [][]string{{"Generated"}}},
+	} {
+		selected, err := selectStructs(tc.pkgs, tc.args, tc.all, tc.excludes)
+		assert.Nil(t, err, tc.name)
+		var names [][]string
+		for _, types := range selected {
+			var pkgNames []string
+			for _, typ := range types {
+				pkgNames = append(pkgNames, typ.Name)
+			}
+			names = append(names, pkgNames)
+		}
+		assert.Equal(t, tc.expected, names, tc.name)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		excludes []string
+	}{
+		{"struct not found", []string{"Missing"}, nil},
+		{"invalid glob", []string{"[User"}, nil},
+		{"invalid regexp", []string{"/(/"}, nil},
+		{"invalid exclude", nil, []string{"/(/"}},
+		{"file in no package", []string{otherFilename}, nil},
+	} {
+		_, err := selectStructs(two, tc.args, false, tc.excludes)
+		assert.NotNil(t, err, tc.name)
+	}
+}
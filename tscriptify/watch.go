@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const watchPollInterval = 100 * time.Millisecond
+
+// watch regenerates the target file every time a Golang file in the models packages (or one of the files given as
+// arguments) changes. Changes are debounced, so that saving multiple files regenerates only once. The packages are
+// reloaded on every change, so that new files and packages (with `./...` patterns) are converted, too. Returns on
+// SIGINT or SIGTERM.
+func watch(p Params, args []string, programFile string) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	dirs, err := watchedDirs(p.ModelsPackages)
+	if err != nil {
+		return err
+	}
+	regenerate := func() {
+		if err := generate(p, args, programFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		}
+	}
+
+	regenerate()
+	fmt.Println("Watching", strings.Join(dirs, ", "), "for changes")
+
+	w := newWatcher(func() string { return watchedFilesSnapshot(dirs, p.ModelsPackages, args) }, p.Debounce)
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-signals:
+			return nil
+		case now := <-ticker.C:
+			if !w.poll(now) {
+				continue
+			}
+			if reloaded, err := watchedDirs(p.ModelsPackages); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err.Error())
+			} else {
+				dirs = reloaded
+			}
+			regenerate()
+		}
+	}
+}
+
+// watchedDirs returns the directories of the models packages.
+func watchedDirs(patterns []string) ([]string, error) {
+	pkgs, err := loadPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, pkg := range pkgs {
+		dirs = append(dirs, pkg.Dir)
+	}
+	return dirs, nil
+}
+
+// watcher detects changes of a snapshot, and debounces them.
+type watcher struct {
+	snapshot  func() string
+	debounce  time.Duration
+	last      string
+	changedAt time.Time
+}
+
+func newWatcher(snapshot func() string, debounce time.Duration) *watcher {
+	return &watcher{snapshot: snapshot, debounce: debounce, last: snapshot()}
+}
+
+// poll returns true if there were changes, and nothing changed for the debounce duration since.
+func (w *watcher) poll(now time.Time) bool {
+	if current := w.snapshot(); current != w.last {
+		w.last = current
+		w.changedAt = now
+		return false
+	}
+	if !w.changedAt.IsZero() && now.Sub(w.changedAt) >= w.debounce {
+		w.changedAt = time.Time{}
+		return true
+	}
+	return false
+}
+
+// watchedFilesSnapshot returns a string which changes when any Golang file in dirs, in directories under local package
+// patterns ending with `/...`, or in files is created, modified or removed.
+func watchedFilesSnapshot(dirs []string, patterns []string, files []string) string {
+	var goFiles []string
+	for _, dir := range dirs {
+		dirFiles, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		goFiles = append(goFiles, dirFiles...)
+	}
+	for _, pattern := range patterns {
+		root := strings.TrimSuffix(pattern, "...")
+		if root == pattern || !(strings.HasPrefix(root, ".") || filepath.IsAbs(root)) {
+			continue
+		}
+		root = filepath.Clean(root)
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				// Not matched by `...` patterns (and never Golang packages):
+				if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" || name == "node_modules") {
+					return filepath.SkipDir
+				}
+			} else if strings.HasSuffix(path, ".go") {
+				goFiles = append(goFiles, path)
+			}
+			return nil
+		})
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".go") {
+			goFiles = append(goFiles, file)
+		}
+	}
+
+	var snapshot strings.Builder
+	for _, file := range goFiles {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&snapshot, "%s:%d:%d\n", file, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return snapshot.String()
+}
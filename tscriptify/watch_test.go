@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherDebounce(t *testing.T) {
+	t.Parallel()
+
+	snapshot := "a"
+	w := newWatcher(func() string { return snapshot }, 300*time.Millisecond)
+	start := time.Now()
+
+	assert.False(t, w.poll(start), "no changes")
+	snapshot = "b"
+	assert.False(t, w.poll(start.Add(100*time.Millisecond)), "changed")
+	snapshot = "c"
+	assert.False(t, w.poll(start.Add(200*time.Millisecond)), "changed again")
+	assert.False(t, w.poll(start.Add(400*time.Millisecond)), "debouncing")
+	assert.True(t, w.poll(start.Add(500*time.Millisecond)), "no changes for 300ms")
+	assert.False(t, w.poll(start.Add(900*time.Millisecond)), "regenerated only once")
+}
+
+func TestWatchedFilesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	models := filepath.Join(root, "models")
+	assert.Nil(t, os.MkdirAll(models, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(models, "models.go"), []byte("package models"), 0644))
+
+	snapshot := watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil)
+	assert.Contains(t, snapshot, "models.go")
+	assert.Equal(t, snapshot, watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil))
+
+	// Directories ignored by `...` patterns are ignored:
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "node_modules", "x"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "node_modules", "x", "x.go"), []byte("package x"), 0644))
+	assert.Equal(t, snapshot, watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil))
+
+	// Non Golang files are ignored:
+	assert.Nil(t, os.WriteFile(filepath.Join(models, "README.md"), []byte("readme"), 0644))
+	assert.Equal(t, snapshot, watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil))
+
+	// New packages under `./...` patterns are found before the packages are reloaded:
+	api := filepath.Join(root, "api")
+	assert.Nil(t, os.MkdirAll(api, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(api, "api.go"), []byte("package api"), 0644))
+	changed := watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil)
+	assert.NotEqual(t, snapshot, changed)
+	assert.Contains(t, changed, "api.go")
+
+	// ...but not without patterns:
+	assert.NotContains(t, watchedFilesSnapshot([]string{models}, []string{"example.com/models"}, nil), "api.go")
+
+	assert.Nil(t, os.WriteFile(filepath.Join(models, "models.go"), []byte("package models\n\ntype A struct{}"), 0644))
+	assert.NotEqual(t, changed, watchedFilesSnapshot([]string{models}, []string{root + "/..."}, nil))
+}
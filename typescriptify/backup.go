@@ -0,0 +1,127 @@
+package typescriptify
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackupDir is where backups of generated files are saved by default.
+const DefaultBackupDir = ".tscriptify-backups"
+
+const (
+	backupSuffix     = ".backup"
+	backupTimeLayout = "2006-01-02T15_04_05.000"
+)
+
+// Backup is a backup of a generated file.
+type Backup struct {
+	Path string
+	Time time.Time
+}
+
+func (t TypeScriptify) backup(fileName string) error {
+	byts, err := os.ReadFile(fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		// No neet to backup, just return:
+		return nil
+	}
+
+	if err := os.MkdirAll(t.BackupDir, 0755); err != nil {
+		return err
+	}
+	// Backups made in the same millisecond (i.e. just before a restore) must not overwrite each other:
+	tm := time.Now()
+	name := backupName(t.BackupDir, fileName)
+	backupFn := filepath.Join(t.BackupDir, fmt.Sprintf("%s-%s%s", name, tm.Format(backupTimeLayout), backupSuffix))
+	for _, err := os.Stat(backupFn); err == nil; _, err = os.Stat(backupFn) {
+		tm = tm.Add(time.Millisecond)
+		backupFn = filepath.Join(t.BackupDir, fmt.Sprintf("%s-%s%s", name, tm.Format(backupTimeLayout), backupSuffix))
+	}
+	if err := os.WriteFile(backupFn, byts, 0644); err != nil {
+		return err
+	}
+
+	return t.pruneBackups(fileName)
+}
+
+// pruneBackups removes backups over the BackupKeep limit, and older than BackupMaxAge.
+func (t TypeScriptify) pruneBackups(fileName string) error {
+	backups, err := ListBackups(t.BackupDir, fileName)
+	if err != nil {
+		return err
+	}
+	for n, backup := range backups {
+		tooMany := t.BackupKeep > 0 && n >= t.BackupKeep
+		tooOld := t.BackupMaxAge > 0 && time.Since(backup.Time) > t.BackupMaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(backup.Path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backupName returns the name of a generated file in the backups: its path relative to the directory containing the
+// backup directory (usually the project), i.e. `web%2Fa%2Fmodels.ts`. Files with the same name in different
+// directories don't share backups.
+func backupName(backupDir, fileName string) string {
+	path := fileName
+	if root, err := filepath.Abs(filepath.Dir(filepath.Clean(backupDir))); err == nil {
+		if abs, err := filepath.Abs(fileName); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+	return url.PathEscape(filepath.ToSlash(path))
+}
+
+// ListBackups returns the backups of a generated file, the newest first.
+func ListBackups(backupDir, fileName string) ([]Backup, error) {
+	prefix := backupName(backupDir, fileName) + "-"
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		// Fractional seconds are optional (older backups have a variable number of digits):
+		tm, err := time.ParseInLocation("2006-01-02T15_04_05", strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupSuffix), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: filepath.Join(backupDir, name), Time: tm})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Time.After(backups[j].Time) })
+	return backups, nil
+}
+
+// RestoreBackup replaces a generated file with a backup. The current file is backed up first (into the directory of
+// the restored backup), so that the restore can be undone.
+func RestoreBackup(backup Backup, fileName string) error {
+	byts, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return err
+	}
+	if err := (TypeScriptify{BackupDir: filepath.Dir(backup.Path)}).backup(fileName); err != nil {
+		return err
+	}
+	return writeFileAtomic(fileName, byts)
+}
@@ -0,0 +1,93 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRetention(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "models.ts")
+	backupDir := filepath.Join(dir, DefaultBackupDir)
+
+	// Older backups, one too old:
+	assert.Nil(t, os.MkdirAll(backupDir, 0755))
+	for _, tm := range []time.Time{time.Now().Add(-time.Hour), time.Now().Add(-48 * time.Hour)} {
+		name := filepath.Join(backupDir, "models.ts-"+tm.Format(backupTimeLayout)+backupSuffix)
+		assert.Nil(t, os.WriteFile(name, []byte("old"), 0644))
+	}
+	assert.Nil(t, os.WriteFile(filepath.Join(backupDir, "other.ts-2020-01-01T00_00_00.000.backup"), []byte("other"), 0644))
+
+	for _, typ := range []interface{}{Address{}, Dummy{}, HasName{}} {
+		_, err := New().WithBackupDir(backupDir).WithBackupRetention(2, 24*time.Hour).Add(typ).ConvertToFileIfChanged(fileName)
+		assert.Nil(t, err)
+	}
+
+	backups, err := ListBackups(backupDir, fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(backups))
+	assert.True(t, backups[0].Time.After(backups[1].Time))
+	info, err := os.Stat(backups[0].Path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// Backups of other files aren't removed:
+	_, err = os.Stat(filepath.Join(backupDir, "other.ts-2020-01-01T00_00_00.000.backup"))
+	assert.Nil(t, err)
+
+	// The last backup is the file before the last conversion:
+	assert.Nil(t, RestoreBackup(backups[0], fileName))
+	restored, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(restored), "export class Dummy {")
+
+	// ...and the restore can be undone:
+	backups, err = ListBackups(backupDir, fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(backups))
+	assert.Nil(t, RestoreBackup(backups[0], fileName))
+	restored, err = os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(restored), "export class HasName {")
+}
+
+func TestBackupsOfFilesWithTheSameName(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, DefaultBackupDir)
+	fileA := filepath.Join(dir, "web", "a", "models.ts")
+	fileB := filepath.Join(dir, "web", "b", "models.ts")
+	assert.Nil(t, os.MkdirAll(filepath.Dir(fileA), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Dir(fileB), 0755))
+	assert.Equal(t, "web%2Fa%2Fmodels.ts", backupName(backupDir, fileA))
+
+	for _, typ := range []interface{}{Address{}, Dummy{}, HasName{}} {
+		_, err := New().WithBackupDir(backupDir).WithBackupRetention(1, 0).Add(typ).ConvertToFileIfChanged(fileA)
+		assert.Nil(t, err)
+	}
+	_, err := New().WithBackupDir(backupDir).WithBackupRetention(1, 0).Add(Address{}).ConvertToFileIfChanged(fileB)
+	assert.Nil(t, err)
+	_, err = New().WithBackupDir(backupDir).WithBackupRetention(1, 0).Add(Dummy{}).ConvertToFileIfChanged(fileB)
+	assert.Nil(t, err)
+
+	backupsA, err := ListBackups(backupDir, fileA)
+	assert.Nil(t, err)
+	backupsB, err := ListBackups(backupDir, fileB)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(backupsA))
+	assert.Equal(t, 1, len(backupsB))
+
+	byts, err := os.ReadFile(backupsA[0].Path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "export class Dummy {")
+	byts, err = os.ReadFile(backupsB[0].Path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "export class Address {")
+}
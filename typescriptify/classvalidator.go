@@ -0,0 +1,127 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	classValidatorModule   = "class-validator"
+	classTransformerModule = "class-transformer"
+)
+
+// classValidatorKinds are the class-validator decorators of primitive kinds.
+var classValidatorKinds = map[reflect.Kind]string{
+	reflect.Bool:    "IsBoolean",
+	reflect.Int:     "IsInt",
+	reflect.Int8:    "IsInt",
+	reflect.Int16:   "IsInt",
+	reflect.Int32:   "IsInt",
+	reflect.Int64:   "IsInt",
+	reflect.Uint:    "IsInt",
+	reflect.Uint8:   "IsInt",
+	reflect.Uint16:  "IsInt",
+	reflect.Uint32:  "IsInt",
+	reflect.Uint64:  "IsInt",
+	reflect.Float32: "IsNumber",
+	reflect.Float64: "IsNumber",
+	reflect.String:  "IsString",
+}
+
+// WithClassValidator adds class-validator (`@IsString()`, `@IsOptional()`, `@ValidateNested()`, ...) and
+// class-transformer (`@Type(() => Address)`) decorators to class properties, i.e. for NestJS validation pipes.
+func (t *TypeScriptify) WithClassValidator(b bool) *TypeScriptify {
+	t.ClassValidator = b
+	return t
+}
+
+// decorateClass returns true if the properties of a struct are decorated (only typescript classes, not interfaces or
+// inlined anonymous structs).
+func (t *TypeScriptify) decorateClass(typeOf reflect.Type) bool {
+	if t.InlineAnonymousStructs && typeOf.Name() == "" {
+		return false
+	}
+	return t.ClassValidator && t.Output == TypeScriptOutput && !t.isInterface(typeOf)
+}
+
+// classValidatorDecorators returns the decorators of a class property, typ is the field type (without the pointer).
+// Fields with custom typescript types are only checked for being optional, discriminators for their value.
+func (t *TypeScriptify) classValidatorDecorators(typ reflect.Type, optional bool, opts TypeOptions, discriminator bool) []string {
+	var decorators []string
+	if optional {
+		decorators = append(decorators, t.validator("IsOptional()"))
+	}
+	switch {
+	case discriminator:
+		return append(decorators, t.validator("Equals("+opts.TSType+")"))
+	case opts.TSType != "" || opts.TSTransform != "":
+		return decorators
+	}
+
+	each := ""
+	switch typ.Kind() {
+	case reflect.Map:
+		return append(decorators, t.validator("IsObject()"))
+	case reflect.Slice, reflect.Array:
+		for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		decorators = append(decorators, t.validator("IsArray()"))
+		each = "{ each: true }"
+	}
+
+	if _, isEnum := t.enums[typ]; isEnum {
+		return append(decorators, t.validator(fmt.Sprintf("IsEnum(%s)", joinArgs(t.Prefix+typ.Name()+t.Suffix, each))))
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		var subTypes []string
+		for _, member := range union.members {
+			subTypes = append(subTypes, fmt.Sprintf("{ value: %s, name: %q }", t.Prefix+t.structName(member.typ)+t.Suffix, member.value))
+		}
+		return append(decorators,
+			t.validator(fmt.Sprintf("ValidateNested(%s)", each)),
+			t.transformer(fmt.Sprintf("Type(() => Object, { discriminator: { property: %q, subTypes: [%s] }, keepDiscriminatorProperty: true })", union.discriminator, strings.Join(subTypes, ", "))))
+	}
+	if typ.Kind() == reflect.Struct {
+		if t.isInterface(typ) {
+			return append(decorators, t.validator(fmt.Sprintf("IsObject(%s)", each)))
+		}
+		return append(decorators,
+			t.validator(fmt.Sprintf("ValidateNested(%s)", each)),
+			t.transformer(fmt.Sprintf("Type(() => %s)", t.Prefix+t.structName(typ)+t.Suffix)))
+	}
+	if validator, found := classValidatorKinds[typ.Kind()]; found {
+		if validator == "IsNumber" && each != "" {
+			return append(decorators, t.validator("IsNumber({}, "+each+")"))
+		}
+		return append(decorators, t.validator(fmt.Sprintf("%s(%s)", validator, each)))
+	}
+	return decorators
+}
+
+// validator returns a class-validator decorator, and marks its import as used.
+func (t *TypeScriptify) validator(call string) string {
+	return t.decorator(classValidatorModule, call)
+}
+
+// transformer returns a class-transformer decorator, and marks its import as used.
+func (t *TypeScriptify) transformer(call string) string {
+	return t.decorator(classTransformerModule, call)
+}
+
+func (t *TypeScriptify) decorator(module, call string) string {
+	name := call[:strings.Index(call, "(")]
+	t.useImport(fmt.Sprintf("import { %s } from '%s';", name, module))
+	return "@" + call
+}
+
+func joinArgs(args ...string) string {
+	var nonEmpty []string
+	for _, arg := range args {
+		if arg != "" {
+			nonEmpty = append(nonEmpty, arg)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
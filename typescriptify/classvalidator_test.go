@@ -0,0 +1,94 @@
+package typescriptify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassValidator(t *testing.T) {
+	t.Parallel()
+
+	type Task struct {
+		Title    string            `json:"title"`
+		Score    *float64          `json:"score"`
+		Scores   []float64         `json:"scores"`
+		Days     []Weekday         `json:"days"`
+		Day      Weekday           `json:"day,omitempty"`
+		Shapes   []Shape           `json:"shapes"`
+		Tags     map[string]string `json:"tags"`
+		Address  *Address          `json:"address"`
+		Previous []*Address        `json:"previous"`
+		Due      string            `json:"due" ts_type:"Date" ts_transform:"new Date(__VALUE__)"`
+	}
+
+	converter := New().WithIndent("\t").WithClassValidator(true).
+		AddEnum(allWeekdaysV2).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(Task{})
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	assert.True(t, strings.HasPrefix(ts, `import { Type } from 'class-transformer';
+import { Equals, IsArray, IsEnum, IsNumber, IsObject, IsOptional, IsString, ValidateNested } from 'class-validator';
+`), ts)
+	assert.Contains(t, ts, `export class Circle {
+	@Equals("circle")
+	kind: "circle";
+	@IsNumber()
+	radius: number;
+`)
+	assert.Contains(t, ts, `export class Task {
+	@IsString()
+	title: string;
+	@IsOptional()
+	@IsNumber()
+	score?: number;
+	@IsArray()
+	@IsNumber({}, { each: true })
+	scores: number[];
+	@IsArray()
+	@IsEnum(Weekday, { each: true })
+	days: number[];
+	@IsOptional()
+	@IsEnum(Weekday)
+	day?: Weekday;
+	@IsArray()
+	@ValidateNested({ each: true })
+	@Type(() => Object, { discriminator: { property: "kind", subTypes: [{ value: Circle, name: "circle" }, { value: Square, name: "square" }] }, keepDiscriminatorProperty: true })
+	shapes: Shape[];
+	@IsObject()
+	tags: {[key: string]: string};
+	@IsOptional()
+	@ValidateNested()
+	@Type(() => Address)
+	address?: Address;
+	@IsArray()
+	@ValidateNested({ each: true })
+	@Type(() => Address)
+	previous: Address[];
+	due: Date;
+`)
+}
+
+func TestClassValidatorOnlyClasses(t *testing.T) {
+	t.Parallel()
+
+	type Meta struct {
+		Person struct {
+			Name string `json:"name"`
+		} `json:"person"`
+	}
+
+	for _, converter := range []*TypeScriptify{
+		New().WithClassValidator(true).WithInterface(true).Add(Address{}),
+		New().WithClassValidator(true).WithOutput(DeclarationOutput).Add(Address{}),
+		New().WithClassValidator(true).WithInlineAnonymousStructs(true).Add(Meta{}),
+	} {
+		ts, err := converter.Convert(nil)
+		assert.Nil(t, err)
+		assert.NotContains(t, ts, "@")
+		assert.NotContains(t, ts, "class-validator")
+	}
+}
@@ -0,0 +1,347 @@
+package typescriptify
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type constant struct {
+	name  string
+	value interface{}
+}
+
+type literalMode int
+
+const (
+	// Object literals typed by the generated interfaces (with typescript field names and transformed values).
+	literalTyped literalMode = iota
+	// Structs instantiated with the constructors of the generated classes.
+	literalConstructed
+	// JSON as read by the constructors (with JSON field names and values).
+	literalJSON
+)
+
+// AddConstant adds a typescript constant, i.e. `AddConstant("MaxUploadSize", MaxUploadSize)` will be converted to
+// `export const MAX_UPLOAD_SIZE = 10485760;`.
+//
+// Values can be numbers, strings, slices, maps and structs. Structs are converted to object literals typed with the
+// generated interfaces, or instantiated with the constructor of the generated classes.
+func (t *TypeScriptify) AddConstant(name string, value interface{}) *TypeScriptify {
+	if typ := valueStructType(value); typ != nil && typ.Name() != "" {
+		t.AddType(typ)
+	}
+	t.constants = append(t.constants, constant{name: name, value: value})
+	return t
+}
+
+// constantTSType returns the declared type of a constant.
+func (t *TypeScriptify) constantTSType(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return t.valueTSType(reflect.TypeOf(value))
+}
+
+// constantName converts `MaxUploadSize` to `MAX_UPLOAD_SIZE` (names without lowercase letters are left as they are).
+func constantName(name string) string {
+	if strings.IndexFunc(name, unicode.IsLower) < 0 {
+		return name
+	}
+	return strings.ToUpper(SnakeCase(name))
+}
+
+func (t *TypeScriptify) convertConstants() (string, error) {
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	var lines []string
+	for _, c := range t.constants {
+		t.logf(0, "Converting constant %s", c.name)
+		mode := literalTyped
+		if !t.CreateInterface && t.CreateConstructor {
+			mode = literalConstructed
+		}
+		literal, err := t.valueLiteral(reflect.ValueOf(c.value), 0, mode)
+		if err != nil {
+			return "", fmt.Errorf("constant %s: %w", c.name, err)
+		}
+		annotation := ""
+		if mode == literalTyped && c.value != nil && valueStructType(c.value) != nil {
+			annotation = ": " + t.valueTSType(reflect.TypeOf(c.value))
+		}
+		switch t.Output {
+		case DeclarationOutput:
+			lines = append(lines, fmt.Sprintf("%sdeclare const %s: %s;", export, constantName(c.name), t.constantTSType(c.value)))
+		case JavaScriptOutput:
+			lines = append(lines, jsDoc("", "@type {"+t.constantTSType(c.value)+"}")+fmt.Sprintf("%sconst %s = %s;", export, constantName(c.name), literal))
+		default:
+			lines = append(lines, fmt.Sprintf("%sconst %s%s = %s;", export, constantName(c.name), annotation, literal))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// valueTSType returns the typescript type of a Golang value type (used to annotate literals).
+func (t *TypeScriptify) valueTSType(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.valueTSType(typ.Elem())
+	case reflect.Slice, reflect.Array:
+		return t.valueTSType(typ.Elem()) + "[]"
+	case reflect.Map:
+		return fmt.Sprintf("{[key: %s]: %s}", t.valueTSType(typ.Key()), t.valueTSType(typ.Elem()))
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return t.Prefix + typ.Name() + t.Suffix
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		return t.unionName(union)
+	}
+	if typ.Kind() == reflect.Struct {
+		return t.Prefix + t.structName(typ) + t.Suffix
+	}
+	if kind, found := t.kinds[typ.Kind()]; found {
+		return kind
+	}
+	return "any"
+}
+
+// valueLiteral renders a Golang value as a typescript literal, using the same field names as the generated models.
+func (t *TypeScriptify) valueLiteral(v reflect.Value, depth int, mode literalMode) (string, error) {
+	if !v.IsValid() {
+		return "null", nil
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null", nil
+		}
+		return t.valueLiteral(v.Elem(), depth, mode)
+	}
+
+	if elements, isEnum := t.enums[v.Type()]; isEnum {
+		for _, el := range elements {
+			value := el.value
+			if el.goValue != nil {
+				value = el.goValue
+			}
+			if reflect.DeepEqual(value, v.Interface()) {
+				return t.Prefix + v.Type().Name() + t.Suffix + "." + el.name, nil
+			}
+		}
+	}
+
+	if isJSONMarshaler(v) {
+		// Pointer, for MarshalJSON methods with pointer receivers:
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		byts, err := json.Marshal(ptr.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(byts), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsNaN(f):
+			return "NaN", nil
+		case math.IsInf(f, 1):
+			return "Infinity", nil
+		case math.IsInf(f, -1):
+			return "-Infinity", nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case reflect.String:
+		byts, err := json.Marshal(v.String())
+		return string(byts), err
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// Base64, as in JSON:
+			byts, err := json.Marshal(v.Interface())
+			return string(byts), err
+		}
+		var elements []string
+		multiline := false
+		for i := 0; i < v.Len(); i++ {
+			literal, err := t.valueLiteral(v.Index(i), depth+1, mode)
+			if err != nil {
+				return "", err
+			}
+			multiline = multiline || strings.ContainsAny(literal, "{\n")
+			elements = append(elements, literal)
+		}
+		return t.collectionLiteral("[", "]", elements, depth, multiline), nil
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		var elements []string
+		for _, key := range keys {
+			literal, err := t.valueLiteral(v.MapIndex(key), depth+1, mode)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, strconv.Quote(fmt.Sprint(key.Interface()))+": "+literal)
+		}
+		return t.collectionLiteral("{", "}", elements, depth, true), nil
+	case reflect.Struct:
+		if mode == literalConstructed && !t.isInterface(v.Type()) {
+			literal, err := t.structLiteral(v, depth, literalJSON)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("new %s(%s)", t.Prefix+t.structName(v.Type())+t.Suffix, literal), nil
+		}
+		return t.structLiteral(v, depth, mode)
+	}
+
+	return "", fmt.Errorf("cannot convert value of type %s", v.Type().String())
+}
+
+func (t *TypeScriptify) structLiteral(v reflect.Value, depth int, mode literalMode) (string, error) {
+	typ := v.Type()
+	dialect := t.tagDialect(typ)
+	discriminators := t.unionDiscriminators(typ)
+
+	var elements []string
+	keys := map[string]bool{}
+	for _, deepField := range t.deepFields(typ, dialect, "") {
+		jsonFieldName, fieldName, fldOpts := t.fieldNames(typ, deepField, dialect)
+		if jsonFieldName == "" || deepField.PkgPath != "" {
+			continue
+		}
+		fieldValue, found := fieldByPath(v, deepField.path)
+		if !found {
+			continue
+		}
+		nilPtr := fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil()
+		if nilPtr && strings.HasSuffix(fieldName, "?") {
+			continue
+		}
+		if dialect.parse(deepField.StructField).omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+
+		var literal string
+		if discriminator, isDiscriminator := discriminators[jsonFieldName]; isDiscriminator {
+			literal = discriminator
+		} else if nilPtr {
+			// Required in typescript (i.e. `ts_optional:"false"` or untagged), but `null` in JSON:
+			literal = "null"
+			if mode == literalTyped {
+				literal = "null as any"
+			}
+		} else {
+			var err error
+			literal, err = t.valueLiteral(emptyCollection(fieldValue), depth+1, mode)
+			if err != nil {
+				return "", err
+			}
+			if mode == literalTyped && fldOpts.TSTransform != "" {
+				literal = strings.Replace(fldOpts.TSTransform, "__VALUE__", literal, -1)
+			}
+		}
+
+		key := jsonFieldName
+		if mode == literalTyped {
+			key = strings.TrimSuffix(fieldName, "?")
+		}
+		keys[jsonFieldName] = true
+		elements = append(elements, tsPropertyName(key)+": "+literal)
+	}
+
+	var discriminatorNames []string
+	for jsonFieldName := range discriminators {
+		if !keys[jsonFieldName] {
+			discriminatorNames = append(discriminatorNames, jsonFieldName)
+		}
+	}
+	sort.Strings(discriminatorNames)
+	for n := len(discriminatorNames) - 1; n >= 0; n-- {
+		key := discriminatorNames[n]
+		literal := discriminators[key]
+		if mode == literalTyped {
+			key = t.getTSFieldName(key)
+		}
+		elements = append([]string{tsPropertyName(key) + ": " + literal}, elements...)
+	}
+
+	return t.collectionLiteral("{", "}", elements, depth, true), nil
+}
+
+// emptyCollection replaces nil slices and maps with empty ones (because the generated types aren't nullable).
+func emptyCollection(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.MakeSlice(v.Type(), 0, 0)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.MakeMap(v.Type())
+		}
+	}
+	return v
+}
+
+// fieldByPath returns the value of a (possibly promoted) field, found is false if an embedded pointer is nil.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() == reflect.Interface { // i.e. protobuf oneofs
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return v, false
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return v, false
+		}
+	}
+	return v, true
+}
+
+func isJSONMarshaler(v reflect.Value) bool {
+	jsonMarshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typ := v.Type()
+	ptrType := reflect.PtrTo(typ)
+	return typ.Implements(jsonMarshaler) || ptrType.Implements(jsonMarshaler) ||
+		typ.Implements(textMarshaler) || ptrType.Implements(textMarshaler)
+}
+
+func (t *TypeScriptify) collectionLiteral(open, close string, elements []string, depth int, multiline bool) string {
+	if len(elements) == 0 {
+		return open + close
+	}
+	if !multiline {
+		return open + strings.Join(elements, ", ") + close
+	}
+	indent := strings.Repeat(t.Indent, depth+1)
+	return open + "\n" + indent + strings.Join(elements, ",\n"+indent) + ",\n" + strings.Repeat(t.Indent, depth) + close
+}
@@ -0,0 +1,177 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const MaxUploadSize = 10 << 20
+
+type Limits struct {
+	PageSize int               `json:"page_size"`
+	Features []string          `json:"features"`
+	Weekday  Weekday           `json:"weekday"`
+	Quotas   map[string]int    `json:"quotas,omitempty"`
+	Since    time.Time         `json:"since" ts_type:"Date" ts_transform:"new Date(__VALUE__)"`
+	Owner    *Address          `json:"owner"`
+	Labels   map[string]string `json:"labels"`
+}
+
+type LimitsHolder struct {
+	Limits Limits `json:"limits"`
+}
+
+func TestConstantName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "MAX_UPLOAD_SIZE", constantName("MaxUploadSize"))
+	assert.Equal(t, "API_URL", constantName("APIUrl"))
+	assert.Equal(t, "DEFAULT_LIMIT", constantName("DEFAULT_LIMIT"))
+}
+
+func TestPrimitiveConstants(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum(allWeekdaysV1).
+		AddConstant("MaxUploadSize", MaxUploadSize).
+		AddConstant("Ratio", 0.75).
+		AddConstant("FeatureKey", "new-\"editor\"").
+		AddConstant("Enabled", true).
+		AddConstant("PageSizes", []int{10, 25, 50}).
+		AddConstant("FirstDay", Monday).
+		AddConstant("Colors", map[string]string{"red": "#f00", "blue": "#00f"})
+
+	desiredResult := `export enum Weekday {
+	SUNDAY = 0,
+	MONDAY = 1,
+	TUESDAY = 2,
+	WEDNESDAY = 3,
+	THURSDAY = 4,
+	FRIDAY = 5,
+	SATURDAY = 6,
+}
+
+export const MAX_UPLOAD_SIZE = 10485760;
+export const RATIO = 0.75;
+export const FEATURE_KEY = "new-\"editor\"";
+export const ENABLED = true;
+export const PAGE_SIZES = [10, 25, 50];
+export const FIRST_DAY = Weekday.MONDAY;
+export const COLORS = {
+	"blue": "#00f",
+	"red": "#f00",
+};`
+	testConverter(t, converter, true, desiredResult, []string{
+		`MAX_UPLOAD_SIZE === 10485760`,
+		`FIRST_DAY === Weekday.MONDAY`,
+	})
+}
+
+func TestStructConstants(t *testing.T) {
+	t.Parallel()
+
+	limits := Limits{
+		PageSize: 25,
+		Weekday:  Friday,
+		Since:    time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Owner:    &Address{Duration: 1.5, Text1: "owner"},
+	}
+
+	converter := New().WithInterface(true).AddEnum(allWeekdaysV1).AddConstant("DefaultLimits", limits)
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const DEFAULT_LIMITS: Limits = {
+    page_size: 25,
+    features: [],
+    weekday: Weekday.FRIDAY,
+    since: new Date("2021-01-02T03:04:05Z"),
+    owner: {
+        duration: 1.5,
+        text: "owner",
+    },
+    labels: {},
+};`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{`DEFAULT_LIMITS.since.getUTCFullYear() === 2021`})
+
+	converter = New().AddEnum(allWeekdaysV1).AddConstant("AllLimits", []Limits{limits})
+	typeScriptCode, err = converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const ALL_LIMITS = [
+    new Limits({
+        page_size: 25,
+        features: [],
+        weekday: Weekday.FRIDAY,
+        since: "2021-01-02T03:04:05Z",
+        owner: {
+            duration: 1.5,
+            text: "owner",
+        },
+        labels: {},
+    }),
+];`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`ALL_LIMITS[0].since instanceof Date`,
+		`ALL_LIMITS[0].owner instanceof Address`,
+	})
+
+	// Nested values are transformed by the constructors:
+	converter = New().AddEnum(allWeekdaysV1).AddConstant("Holder", LimitsHolder{Limits: limits})
+	typeScriptCode, err = converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const HOLDER = new LimitsHolder({
+    limits: {
+        page_size: 25,`)
+	assert.Contains(t, typeScriptCode, `        since: "2021-01-02T03:04:05Z",`)
+}
+
+func TestNilPointerConstants(t *testing.T) {
+	t.Parallel()
+
+	type Contact struct {
+		Name     string   `json:"name"`
+		Address  *Address `json:"address"`
+		Previous *Address `json:"previous" ts_optional:"false"`
+		Other    *Address
+	}
+
+	converter := New().WithInterface(true).AddConstant("Nobody", Contact{Name: "a"})
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const NOBODY: Contact = {
+    name: "a",
+    previous: null as any,
+    Other: null as any,
+};`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{`NOBODY.Other === null`})
+
+	converter = New().AddConstant("Nobody", Contact{Name: "a"})
+	typeScriptCode, err = converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const NOBODY = new Contact({
+    name: "a",
+    previous: null,
+    Other: null,
+});`)
+}
+
+func TestUnionConstants(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		WithInterface(true).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		AddConstant("DefaultDrawing", Drawing{Main: &Square{Side: 2}})
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const DEFAULT_DRAWING: Drawing = {
+    main: {
+        kind: "square",
+        side: 2,
+    },
+    shapes: [],
+    by_name: {},
+};`)
+}
@@ -0,0 +1,147 @@
+package typescriptify
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// CustomCodeHeader is the name of the custom code block at the top of the file (i.e. for imports).
+	CustomCodeHeader = "__header"
+	// Prefix of quarantined blocks of types which are no longer generated.
+	orphanedCustomCodePrefix = "__orphaned:"
+)
+
+// OrphanedCustomCode is what happens with custom code blocks of types which are no longer generated (i.e. renamed).
+type OrphanedCustomCode int
+
+const (
+	// WarnOrphanedCustomCode prints a warning, and the orphaned code is removed.
+	WarnOrphanedCustomCode OrphanedCustomCode = iota
+	// FailOnOrphanedCustomCode makes the conversion fail (and the file is left untouched).
+	FailOnOrphanedCustomCode
+	// QuarantineOrphanedCustomCode moves orphaned blocks (commented out) to the end of the file. If the type is generated
+	// again, the code is moved back.
+	QuarantineOrphanedCustomCode
+)
+
+func (t *TypeScriptify) WithOrphanedCustomCode(o OrphanedCustomCode) *TypeScriptify {
+	t.OrphanedCustomCode = o
+	return t
+}
+
+// loadCustomCode reads the `//[Name:]` ... `//[end]` blocks of a previously generated file.
+func loadCustomCode(fileName string) (map[string]string, error) {
+	result := make(map[string]string)
+	byts, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return result, err
+	}
+	return parseCustomCode(fileName, string(byts))
+}
+
+func parseCustomCode(fileName, content string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	var currentName string
+	var currentValue string
+	var currentLine int
+	for n, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "//[") && strings.HasSuffix(trimmedLine, ":]") {
+			if currentName != "" {
+				return nil, fmt.Errorf("%s:%d: custom code block %s opened before the block %s (line %d) was closed with //[end]", fileName, n+1, trimmedLine, currentName, currentLine)
+			}
+			currentName = strings.TrimSuffix(strings.TrimPrefix(trimmedLine, "//["), ":]")
+			currentValue = ""
+			currentLine = n + 1
+			if _, found := result[currentName]; found {
+				return nil, fmt.Errorf("%s:%d: duplicate custom code block %s", fileName, n+1, currentName)
+			}
+		} else if trimmedLine == "//[end]" {
+			if currentName == "" {
+				return nil, fmt.Errorf("%s:%d: //[end] without a custom code block", fileName, n+1)
+			}
+			result[currentName] = strings.TrimRight(currentValue, " \t\r\n")
+			currentName = ""
+			currentValue = ""
+		} else if len(currentName) > 0 {
+			currentValue += line + "\n"
+		}
+	}
+	if currentName != "" {
+		return nil, fmt.Errorf("%s:%d: custom code block %s not closed with //[end]", fileName, currentLine, currentName)
+	}
+
+	return result, nil
+}
+
+// customCodeBlock returns the custom code block of a type (or the header), empty if there is no custom code.
+func (t *TypeScriptify) customCodeBlock(customCode map[string]string, name, indent string) string {
+	code := customCode[name]
+	if quarantined := customCode[orphanedCustomCodePrefix+name]; code == "" && quarantined != "" {
+		t.logf(0, "Restoring quarantined custom code of %s", name)
+		code = uncommentLines(quarantined)
+		t.usedCustomCode[orphanedCustomCodePrefix+name] = true
+	}
+	if code == "" {
+		return ""
+	}
+	t.usedCustomCode[name] = true
+	return indent + "//[" + name + ":]\n" + code + "\n\n" + indent + "//[end]\n"
+}
+
+// orphanedCustomCode handles the custom code blocks not used in the conversion, returns the quarantine section.
+func (t *TypeScriptify) orphanedCustomCode(customCode map[string]string) (string, error) {
+	var orphans []string
+	for name, code := range customCode {
+		if !t.usedCustomCode[name] && strings.TrimSpace(code) != "" {
+			orphans = append(orphans, name)
+		}
+	}
+	if len(orphans) == 0 {
+		return "", nil
+	}
+	sort.Strings(orphans)
+
+	switch t.OrphanedCustomCode {
+	case FailOnOrphanedCustomCode:
+		return "", fmt.Errorf("custom code of types which are no longer generated: %s", strings.Join(orphans, ", "))
+	case QuarantineOrphanedCustomCode:
+		result := "// Custom code of types which are no longer generated:\n"
+		for _, name := range orphans {
+			code := customCode[name]
+			if !strings.HasPrefix(name, orphanedCustomCodePrefix) {
+				t.logf(0, "Quarantining custom code of %s", name)
+				name, code = orphanedCustomCodePrefix+name, commentLines(code)
+			}
+			result += "//[" + name + ":]\n" + code + "\n//[end]\n"
+		}
+		return result, nil
+	}
+	for _, name := range orphans {
+		fmt.Fprintf(os.Stderr, "WARNING: removed custom code of %s (no longer generated)\n", name)
+	}
+	return "", nil
+}
+
+func commentLines(code string) string {
+	lines := strings.Split(code, "\n")
+	for n := range lines {
+		lines[n] = "// " + lines[n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func uncommentLines(code string) string {
+	lines := strings.Split(code, "\n")
+	for n := range lines {
+		lines[n] = strings.TrimPrefix(strings.TrimPrefix(lines[n], "//"), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,108 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCustomCode(t *testing.T) {
+	t.Parallel()
+
+	code, err := parseCustomCode("models.ts", `//[__header:]
+import { Decimal } from 'decimal.js';
+//[end]
+export class Address {
+    //[Address:]
+    full() { return ""; }
+    //[end]
+}`)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		CustomCodeHeader: "import { Decimal } from 'decimal.js';",
+		"Address":        `    full() { return ""; }`,
+	}, code)
+
+	for _, invalid := range []string{
+		"//[A:]\n//[B:]\n//[end]",
+		"//[A:]\n",
+		"//[end]",
+		"//[A:]\n//[end]\n//[A:]\n//[end]",
+	} {
+		_, err := parseCustomCode("models.ts", invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestCustomCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	assert.Nil(t, os.WriteFile(fileName, []byte(`//[__header:]
+import { Decimal } from 'decimal.js';
+//[end]
+export enum Weekday {
+    //[Weekday:]
+    HOLIDAY = 100,
+    //[end]
+}
+export class Dummy {
+    //[Dummy:]
+    hello() { return "hello"; }
+    //[end]
+}`), 0644))
+
+	converter := New().WithBackupDir("").AddEnum(allWeekdaysV1).Add(Dummy{})
+	_, err := converter.ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), `/* Do not change, this code is generated from Golang structs */
+
+//[__header:]
+import { Decimal } from 'decimal.js';
+
+//[end]
+`)
+	assert.Contains(t, string(byts), `    SATURDAY = 6,
+    //[Weekday:]
+    HOLIDAY = 100,
+
+    //[end]
+}`)
+	assert.Contains(t, string(byts), `    //[Dummy:]
+    hello() { return "hello"; }
+
+    //[end]
+}`)
+
+	// Dummy was renamed:
+	_, err = New().WithBackupDir("").AddEnum(allWeekdaysV1).Add(Address{}).
+		WithOrphanedCustomCode(FailOnOrphanedCustomCode).ConvertToFileIfChanged(fileName)
+	assert.NotNil(t, err)
+
+	_, err = New().WithBackupDir("").AddEnum(allWeekdaysV1).Add(Address{}).
+		WithOrphanedCustomCode(QuarantineOrphanedCustomCode).ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	byts, err = os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), `// Custom code of types which are no longer generated:
+//[__orphaned:Dummy:]
+//     hello() { return "hello"; }
+//[end]`)
+
+	// ...and back:
+	_, err = New().WithBackupDir("").AddEnum(allWeekdaysV1).Add(Dummy{}).
+		WithOrphanedCustomCode(QuarantineOrphanedCustomCode).ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	byts, err = os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), `    //[Dummy:]
+    hello() { return "hello"; }
+
+    //[end]
+}`)
+	assert.NotContains(t, string(byts), "__orphaned")
+}
@@ -0,0 +1,262 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const tsAPIHelpers = `let apiBaseURL = "";
+let apiRequestInit: RequestInit = {};
+
+__EXPORT__function configureAPI(baseURL: string, init: RequestInit = {}) {
+	apiBaseURL = baseURL;
+	apiRequestInit = init;
+}
+
+function apiQueryString(params: any): string {
+	const parts: string[] = [];
+	for (const key of Object.keys(params || {})) {
+		const values: any[] = Array.isArray(params[key]) ? params[key] : [params[key]];
+		for (const value of values) {
+			if (value !== undefined && value !== null) {
+				parts.push(encodeURIComponent(key) + "=" + encodeURIComponent(String(value)));
+			}
+		}
+	}
+	return parts.length ? "?" + parts.join("&") : "";
+}
+
+function apiFetch(method: string, url: string, body?: any): Promise<any> {
+	const init: RequestInit = {...apiRequestInit, method: method};
+	if (body !== undefined) {
+		const headers = new Headers(apiRequestInit.headers);
+		headers.set("Content-Type", "application/json");
+		init.headers = headers;
+		init.body = JSON.stringify(body);
+	}
+	return fetch(apiBaseURL + url, init).then(response => {
+		if (!response.ok) {
+			throw new Error(method + " " + url + ": " + response.status + " " + response.statusText);
+		}
+		if (response.status === 204) {
+			return undefined;
+		}
+		return response.json();
+	});
+}`
+
+// Endpoint describes one HTTP endpoint of the generated API client.
+type Endpoint struct {
+	// Method is the HTTP method, i.e. "GET".
+	Method string
+	// Path with parameters in braces, i.e. "/users/{id}".
+	Path string
+	// Name of the generated function, by default derived from the method and path (i.e. `getUsersById`).
+	Name string
+	// Query is a struct with `query` tags, its fields are sent as query parameters (the typescript model keeps the
+	// converter's tag dialect, only the parameter names are taken from `query` tags).
+	Query interface{}
+	// Request is sent as the JSON body.
+	Request interface{}
+	// Response is a struct (or slice of structs) returned as JSON, nil if the endpoint returns nothing.
+	Response interface{}
+}
+
+// AddEndpoint adds an endpoint to the generated API client.
+//
+// For GET, HEAD and DELETE requests the request is a struct with `query` tags (sent as query parameters), for other
+// methods it's sent as the JSON body. Use `AddAPIEndpoint()` if you need both.
+func (t *TypeScriptify) AddEndpoint(method, path string, request, response interface{}) *TypeScriptify {
+	endpoint := Endpoint{Method: method, Path: path, Response: response}
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "DELETE":
+		endpoint.Query = request
+	default:
+		endpoint.Request = request
+	}
+	return t.AddAPIEndpoint(endpoint)
+}
+
+// AddAPIEndpoint adds an endpoint to the generated API client.
+func (t *TypeScriptify) AddAPIEndpoint(endpoint Endpoint) *TypeScriptify {
+	for _, i := range []interface{}{endpoint.Query, endpoint.Request, endpoint.Response} {
+		if typ := valueStructType(i); typ != nil {
+			t.AddType(typ)
+		}
+	}
+	t.endpoints = append(t.endpoints, endpoint)
+	return t
+}
+
+// valueStructType returns the struct type (also from pointers, slices and maps) to be converted, or nil.
+func valueStructType(i interface{}) reflect.Type {
+	if i == nil {
+		return nil
+	}
+	typ, is := i.(reflect.Type)
+	if !is {
+		typ = reflect.TypeOf(i)
+	}
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return typ
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}]+)\}`)
+
+// pathParams returns the parameter names of a path pattern, i.e. `id` for "/users/{id}".
+func pathParams(path string) []string {
+	var result []string
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		result = append(result, pathParamName(match[1]))
+	}
+	return result
+}
+
+func pathParamName(param string) string {
+	param = strings.TrimSuffix(param, "...")
+	if tsIdentifierRegexp.MatchString(param) {
+		return param
+	}
+	return CamelCase(param)
+}
+
+func endpointFunctionName(endpoint Endpoint) string {
+	if endpoint.Name != "" {
+		return endpoint.Name
+	}
+	name := strings.ToLower(endpoint.Method)
+	for _, segment := range strings.Split(endpoint.Path, "/") {
+		if segment == "" {
+			continue
+		}
+		if match := pathParamRegexp.FindStringSubmatch(segment); match != nil {
+			segment = "By_" + pathParamName(match[1])
+		}
+		name += upperFirst(CamelCase(segment))
+	}
+	return name
+}
+
+// endpointType returns the typescript type of a request/response, and an expression converting the JSON `json`.
+func (t *TypeScriptify) endpointType(i interface{}) (string, string) {
+	if i == nil {
+		return "void", "undefined"
+	}
+	typ, is := i.(reflect.Type)
+	if !is {
+		typ = reflect.TypeOf(i)
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	arrayDepth := 0
+	for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+		arrayDepth++
+		for typ.Kind() == reflect.Ptr { // i.e. `[]*User`
+			typ = typ.Elem()
+		}
+	}
+	if typ.Kind() == reflect.Struct {
+		name := t.Prefix + t.structName(typ) + t.Suffix
+		tsType := name + strings.Repeat("[]", arrayDepth)
+		if t.isInterface(typ) || !t.CreateConstructor {
+			return tsType, "json as " + tsType
+		}
+		if arrayDepth == 0 {
+			return tsType, "new " + name + "(json)"
+		}
+		expr := "new " + name + "(elem)"
+		for n := 1; n < arrayDepth; n++ {
+			expr = "(elem as any[]).map(elem => " + expr + ")"
+		}
+		return tsType, "(json as any[]).map(elem => " + expr + ")"
+	}
+	tsType := "any"
+	if kind, found := t.kinds[typ.Kind()]; found {
+		tsType = kind
+	}
+	tsType += strings.Repeat("[]", arrayDepth)
+	return tsType, "json as " + tsType
+}
+
+// endpointQueryParams returns an expression with the query parameters (named by `query` tags) of the `query` argument.
+// The query struct is converted with the converter's tag dialect, so the parameters are mapped from the TS properties.
+func (t *TypeScriptify) endpointQueryParams(query interface{}) string {
+	typ := valueStructType(query)
+	if typ == nil {
+		return "query"
+	}
+	tsNames := map[string]string{}
+	dialect := t.tagDialect(typ)
+	for _, field := range t.deepFields(typ, dialect, "") {
+		if _, tsName, _ := t.fieldNames(typ, field, dialect); tsName != "" {
+			tsNames[field.path] = strings.TrimSuffix(tsName, "?")
+		}
+	}
+	var params []string
+	for _, field := range t.deepFields(typ, QueryTags, "") {
+		tsName, found := tsNames[field.path]
+		if !found {
+			continue
+		}
+		name := strings.TrimSuffix(t.getJSONFieldName(field.StructField, false, QueryTags), "?")
+		if name == "" {
+			continue
+		}
+		if tsIdentifierRegexp.MatchString(tsName) {
+			params = append(params, fmt.Sprintf("%q: query.%s", name, tsName))
+		} else {
+			params = append(params, fmt.Sprintf("%q: query[%q]", name, tsName))
+		}
+	}
+	return "{" + strings.Join(params, ", ") + "}"
+}
+
+func (t *TypeScriptify) convertEndpoints() string {
+	if len(t.endpoints) == 0 {
+		return ""
+	}
+
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	result := strings.ReplaceAll(strings.ReplaceAll(tsAPIHelpers, "\t", t.Indent), "__EXPORT__", export)
+	for _, endpoint := range t.endpoints {
+		t.logf(0, "Converting endpoint %s %s", endpoint.Method, endpoint.Path)
+
+		var args []string
+		for _, param := range pathParams(endpoint.Path) {
+			args = append(args, param+": string | number")
+		}
+		url := "`" + pathParamRegexp.ReplaceAllStringFunc(endpoint.Path, func(param string) string {
+			return "${encodeURIComponent(String(" + pathParamName(param[1:len(param)-1]) + "))}"
+		}) + "`"
+		if endpoint.Query != nil {
+			queryType, _ := t.endpointType(endpoint.Query)
+			args = append(args, "query: "+queryType)
+			url += " + apiQueryString(" + t.endpointQueryParams(endpoint.Query) + ")"
+		}
+		fetchArgs := fmt.Sprintf("%q, %s", strings.ToUpper(endpoint.Method), url)
+		if endpoint.Request != nil {
+			requestType, _ := t.endpointType(endpoint.Request)
+			args = append(args, "body: "+requestType)
+			fetchArgs += ", body"
+		}
+		responseType, responseExpr := t.endpointType(endpoint.Response)
+
+		result += fmt.Sprintf("\n\n%sfunction %s(%s): Promise<%s> {\n", export, endpointFunctionName(endpoint), strings.Join(args, ", "), responseType)
+		result += fmt.Sprintf("%sreturn apiFetch(%s).then(json => %s);\n", t.Indent, fetchArgs, responseExpr)
+		result += "}"
+	}
+	return result
+}
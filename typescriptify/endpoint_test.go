@@ -0,0 +1,126 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type APIUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type APIUserQuery struct {
+	Search string   `json:"search" query:"q"`
+	Roles  []string `json:"roles" query:"role"`
+	Page   int      `json:"page,omitempty" query:"page,omitempty"`
+}
+
+type APICreateUser struct {
+	Name string `json:"name"`
+}
+
+func TestEndpointFunctionNames(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "getUsersById", endpointFunctionName(Endpoint{Method: "GET", Path: "/users/{id}"}))
+	assert.Equal(t, "postUsers", endpointFunctionName(Endpoint{Method: "POST", Path: "/users"}))
+	assert.Equal(t, "deleteUsersByUserIdRoles", endpointFunctionName(Endpoint{Method: "DELETE", Path: "/users/{user-id}/roles"}))
+	assert.Equal(t, "listUsers", endpointFunctionName(Endpoint{Method: "GET", Path: "/users", Name: "listUsers"}))
+	assert.Equal(t, []string{"org", "path"}, pathParams("/orgs/{org}/files/{path...}"))
+}
+
+func TestEndpoints(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEndpoint("GET", "/users/{id}", nil, APIUser{}).
+		AddEndpoint("GET", "/users", APIUserQuery{}, []APIUser{}).
+		AddEndpoint("POST", "/users", APICreateUser{}, &APIUser{}).
+		AddAPIEndpoint(Endpoint{Method: "DELETE", Path: "/users/{id}", Name: "deleteUser"})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	assert.Contains(t, typeScriptCode, `export class APIUserQuery {
+    search: string;
+    roles: string[];
+    page?: number;`)
+	assert.Contains(t, typeScriptCode, `export function getUsersById(id: string | number): Promise<APIUser> {
+    return apiFetch("GET", `+"`/users/${encodeURIComponent(String(id))}`"+`).then(json => new APIUser(json));
+}`)
+	assert.Contains(t, typeScriptCode, `export function getUsers(query: APIUserQuery): Promise<APIUser[]> {
+    return apiFetch("GET", `+"`/users`"+` + apiQueryString({"q": query.search, "role": query.roles, "page": query.page})).then(json => (json as any[]).map(elem => new APIUser(elem)));
+}`)
+	assert.Contains(t, typeScriptCode, `export function postUsers(body: APICreateUser): Promise<APIUser> {
+    return apiFetch("POST", `+"`/users`"+`, body).then(json => new APIUser(json));
+}`)
+	assert.Contains(t, typeScriptCode, `export function deleteUser(id: string | number): Promise<void> {
+    return apiFetch("DELETE", `+"`/users/${encodeURIComponent(String(id))}`"+`).then(json => undefined);
+}`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`apiQueryString({q: "a b", role: ["x", "y"]}) === "?q=a%20b&role=x&role=y"`,
+		`apiQueryString({}) === ""`,
+	})
+}
+
+func TestEndpointPointerResponses(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEndpoint("GET", "/users", nil, []*APIUser{}).
+		AddEndpoint("GET", "/users/pages", nil, [][]*APIUser{})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export function getUsers(): Promise<APIUser[]> {
+    return apiFetch("GET", `+"`/users`"+`).then(json => (json as any[]).map(elem => new APIUser(elem)));
+}`)
+	assert.Contains(t, typeScriptCode, `export function getUsersPages(): Promise<APIUser[][]> {
+    return apiFetch("GET", `+"`/users/pages`"+`).then(json => (json as any[]).map(elem => (elem as any[]).map(elem => new APIUser(elem))));
+}`)
+	testTypescriptExpression(t, true, typeScriptCode, nil)
+}
+
+func TestEndpointQueryNames(t *testing.T) {
+	t.Parallel()
+
+	type Filter struct {
+		Search   string `json:"search" query:"q" ts_name:"text"`
+		PageSize int    `json:"page_size" query:"limit"`
+		Internal string `json:"internal" query:"-"`
+		Sort     string `json:"-" query:"sort"`
+	}
+
+	converter := New().
+		WithTSPropertyNamer(CamelCase).
+		AddEndpoint("GET", "/users", Filter{}, []APIUser{}).
+		AddEndpoint("POST", "/users/search", Filter{}, []APIUser{})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	// The query struct is also a JSON body, so it keeps the JSON names:
+	assert.Contains(t, typeScriptCode, `export class Filter {
+    text: string;
+    pageSize: number;
+    internal: string;`)
+	assert.Contains(t, typeScriptCode, `source["page_size"]`)
+	assert.Contains(t, typeScriptCode, "apiFetch(\"GET\", `/users` + apiQueryString({\"q\": query.text, \"limit\": query.pageSize}))")
+}
+
+func TestEndpointsWithInterfaces(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		WithInterface(true).
+		AddEndpoint("GET", "/users", nil, []APIUser{})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export interface APIUser {`)
+	assert.Contains(t, typeScriptCode, `export function getUsers(): Promise<APIUser[]> {
+    return apiFetch("GET", `+"`/users`"+`).then(json => json as APIUser[]);
+}`)
+}
@@ -0,0 +1,235 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const tsFakeTag = "ts_fake"
+
+// FakeHints are the named values of the `ts_fake` tag, other tag values are used as typescript expressions (i.e.
+// `ts_fake:"new Date(0)"`).
+var FakeHints = map[string]string{
+	"email":      `"jane.doe@example.com"`,
+	"url":        `"https://example.com"`,
+	"uuid":       `"00000000-0000-4000-8000-000000000001"`,
+	"name":       `"Jane Doe"`,
+	"first_name": `"Jane"`,
+	"last_name":  `"Doe"`,
+	"phone":      `"+1 555 0100"`,
+	"date":       `"2024-01-01"`,
+	"datetime":   `"2024-01-01T00:00:00Z"`,
+	"lorem":      `"Lorem ipsum dolor sit amet"`,
+}
+
+func (t *TypeScriptify) fakeFunctionName(typeOf reflect.Type) string {
+	return "fake" + t.Prefix + t.structName(typeOf) + t.Suffix
+}
+
+// convertFake returns a `fakePerson(overrides?: Partial<Person>): Person` function which creates a model with
+// deterministic values.
+func (t *TypeScriptify) convertFake(typeOf reflect.Type) (string, error) {
+	entityName := t.Prefix + t.structName(typeOf) + t.Suffix
+	counter := 0
+	elements, err := t.fakeFields(typeOf, typeOf, 1, &counter)
+	if err != nil {
+		return "", err
+	}
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	var result string
+	switch t.Output {
+	case DeclarationOutput:
+		return fmt.Sprintf("%sdeclare function %s(overrides?: Partial<%s>): %s;", export, t.fakeFunctionName(typeOf), entityName, entityName), nil
+	case JavaScriptOutput:
+		result = jsDoc("", "@param {Partial<"+entityName+">} [overrides]", "@returns {"+entityName+"}")
+		result += fmt.Sprintf("%sfunction %s(overrides) {\n", export, t.fakeFunctionName(typeOf))
+		// No typescript casts in javascript:
+		for n := range elements {
+			elements[n] = strings.ReplaceAll(elements[n], "undefined as any", "undefined")
+		}
+	default:
+		result = fmt.Sprintf("%sfunction %s(overrides?: Partial<%s>): %s {\n", export, t.fakeFunctionName(typeOf), entityName, entityName)
+	}
+	if t.isInterface(typeOf) {
+		result += t.Indent + "return " + t.collectionLiteral("{", "}", append(elements, "...overrides"), 1, true) + ";\n"
+	} else {
+		// Not with the constructor, because it reads JSON field names (and overrides are typescript properties):
+		result += fmt.Sprintf("%sreturn Object.assign(Object.create(%s.prototype), %s, overrides);\n", t.Indent, entityName, t.collectionLiteral("{", "}", elements, 1, true))
+	}
+	result += "}"
+	return result, nil
+}
+
+// fakeFields returns the `name: value` lines of a fake object, root is the struct of the fake function (fields
+// referencing it aren't faked, to avoid infinite recursion).
+func (t *TypeScriptify) fakeFields(typeOf, root reflect.Type, depth int, counter *int) ([]string, error) {
+	dialect := t.tagDialect(typeOf)
+	t.tagDialects = append(t.tagDialects, dialect)
+	defer func() { t.tagDialects = t.tagDialects[:len(t.tagDialects)-1] }()
+
+	discriminators := t.unionDiscriminators(typeOf)
+	keys := map[string]bool{}
+	var elements []string
+	for _, deepField := range t.deepFields(typeOf, dialect, "") {
+		jsonFieldName, fieldName, fldOpts := t.fieldNames(typeOf, deepField, dialect)
+		if jsonFieldName == "" {
+			continue
+		}
+		fieldName = strings.TrimSuffix(fieldName, "?")
+		keys[jsonFieldName] = true
+		defer t.bindAnonymousStruct(typeOf, deepField)()
+
+		literal, found := discriminators[jsonFieldName]
+		if !found {
+			var err error
+			literal, err = t.fakeFieldValue(deepField.Type, fieldName, fldOpts, root, depth+1, counter)
+			if err != nil {
+				return nil, fmt.Errorf("cannot fake %s.%s: %w", typeOf.Name(), deepField.path, err)
+			}
+		}
+		elements = append(elements, tsPropertyName(fieldName)+": "+literal)
+	}
+
+	var discriminatorNames []string
+	for jsonFieldName := range discriminators {
+		if !keys[jsonFieldName] {
+			discriminatorNames = append(discriminatorNames, jsonFieldName)
+		}
+	}
+	sort.Strings(discriminatorNames)
+	for n := len(discriminatorNames) - 1; n >= 0; n-- {
+		jsonFieldName := discriminatorNames[n]
+		elements = append([]string{tsPropertyName(t.getTSFieldName(jsonFieldName)) + ": " + discriminators[jsonFieldName]}, elements...)
+	}
+
+	return elements, nil
+}
+
+func (t *TypeScriptify) fakeFieldValue(typ reflect.Type, name string, opts TypeOptions, root reflect.Type, depth int, counter *int) (string, error) {
+	if opts.TSFake != "" {
+		if hint, found := FakeHints[opts.TSFake]; found {
+			return hint, nil
+		}
+		return opts.TSFake, nil
+	}
+	if opts.TSType != "" {
+		return t.fakeTSTypeValue(opts.TSType, name, counter), nil
+	}
+	literal, err := t.fakeValue(typ, name, root, depth, counter)
+	if err != nil {
+		return "", err
+	}
+	if literal == "" { // Recursive struct
+		return "undefined as any", nil
+	}
+	return literal, nil
+}
+
+// fakeTSTypeValue returns a fake value for fields with a custom typescript type.
+func (t *TypeScriptify) fakeTSTypeValue(tsType, name string, counter *int) string {
+	switch {
+	case tsType == "string":
+		return strconv.Quote(name)
+	case tsType == "number":
+		*counter++
+		return strconv.Itoa(*counter)
+	case tsType == "boolean":
+		return "true"
+	case tsType == "Date":
+		return `new Date("2024-01-01T00:00:00Z")`
+	case strings.HasSuffix(tsType, "[]"):
+		return "[]"
+	case strings.HasPrefix(tsType, `"`) || strings.HasPrefix(tsType, "'"):
+		// String literal or union of literals:
+		return strings.TrimSpace(strings.Split(tsType, "|")[0])
+	}
+	return "undefined as any"
+}
+
+// fakeValue returns a fake typescript value for a Golang type, or an empty string for structs referencing the root struct.
+func (t *TypeScriptify) fakeValue(typ reflect.Type, name string, root reflect.Type, depth int, counter *int) (string, error) {
+	if typ.Kind() == reflect.Ptr {
+		return t.fakeValue(typ.Elem(), name, root, depth, counter)
+	}
+	if elements, isEnum := t.enums[typ]; isEnum && len(elements) > 0 {
+		return t.Prefix + typ.Name() + t.Suffix + "." + elements[0].name, nil
+	}
+	if union, isUnion := t.unions[typ]; isUnion && len(union.members) > 0 {
+		return t.fakeValue(union.members[0].typ, name, root, depth, counter)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "true", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		*counter++
+		return strconv.Itoa(*counter), nil
+	case reflect.String:
+		return strconv.Quote(name), nil
+	case reflect.Interface:
+		return "null", nil
+	case reflect.Slice, reflect.Array:
+		elem, err := t.fakeValue(typ.Elem(), name, root, depth, counter)
+		if err != nil || elem == "" {
+			return "[]", err
+		}
+		return "[" + elem + "]", nil
+	case reflect.Map:
+		elem, err := t.fakeValue(typ.Elem(), name, root, depth, counter)
+		if err != nil || elem == "" {
+			return "{}", err
+		}
+		key := `"key"`
+		if t.kinds[typ.Key().Kind()] == "number" {
+			key = "1"
+		}
+		return "{" + key + ": " + elem + "}", nil
+	case reflect.Struct:
+		if typ == root || t.structReferences(typ, root, map[reflect.Type]bool{}) {
+			return "", nil
+		}
+		if typ.Name() == "" && t.InlineAnonymousStructs {
+			elements, err := t.fakeFields(typ, root, depth, counter)
+			if err != nil {
+				return "", err
+			}
+			return t.collectionLiteral("{", "}", elements, depth, true), nil
+		}
+		return t.fakeFunctionName(typ) + "()", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", typ.String())
+}
+
+// structReferences returns true if any field (also in nested structs) of typ references target.
+func (t *TypeScriptify) structReferences(typ, target reflect.Type, visited map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return t.structReferences(typ.Elem(), target, visited)
+	case reflect.Interface:
+		if union, isUnion := t.unions[typ]; isUnion && len(union.members) > 0 {
+			return t.structReferences(union.members[0].typ, target, visited)
+		}
+	case reflect.Struct:
+		if typ == target {
+			return true
+		}
+		if visited[typ] {
+			return false
+		}
+		visited[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			if t.structReferences(typ.Field(i).Type, target, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FakeTreeNode struct {
+	Label    string          `json:"label"`
+	Children []*FakeTreeNode `json:"children"`
+}
+
+type FakePerson struct {
+	Email    string            `json:"email" ts_fake:"email"`
+	Nick     string            `json:"nick" ts_fake:"'Ali'"`
+	Age      int               `json:"age"`
+	Weight   float64           `json:"weight"`
+	Active   bool              `json:"active"`
+	Day      Weekday           `json:"day"`
+	Tags     []string          `json:"tags"`
+	Scores   map[string]int    `json:"scores"`
+	Address  *Address          `json:"address"`
+	Extra    interface{}       `json:"extra"`
+	Tree     FakeTreeNode      `json:"tree"`
+	Children []FakePerson      `json:"children"`
+	Labels   map[string]string `json:"-"`
+}
+
+func TestFakesWithInterfaces(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithInterface(true).WithFakes(true).AddEnum(allWeekdaysV1).Add(FakePerson{})
+
+	typeScriptCode, err := converter.WithIndent("\t").Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export function fakeAddress(overrides?: Partial<Address>): Address {
+	return {
+		duration: 1,
+		text: "text",
+		...overrides,
+	};
+}`)
+	assert.Contains(t, typeScriptCode, `export function fakeFakeTreeNode(overrides?: Partial<FakeTreeNode>): FakeTreeNode {
+	return {
+		label: "label",
+		children: [],
+		...overrides,
+	};
+}`)
+	assert.Contains(t, typeScriptCode, `export function fakeFakePerson(overrides?: Partial<FakePerson>): FakePerson {
+	return {
+		email: "jane.doe@example.com",
+		nick: 'Ali',
+		age: 1,
+		weight: 2,
+		active: true,
+		day: Weekday.SUNDAY,
+		tags: ["tags"],
+		scores: {"key": 3},
+		address: fakeAddress(),
+		extra: null,
+		tree: fakeFakeTreeNode(),
+		children: [],
+		...overrides,
+	};
+}`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`fakeFakePerson().email === "jane.doe@example.com"`,
+		`fakeFakePerson({age: 40}).age === 40`,
+		`fakeFakePerson().address.text === "text"`,
+	})
+}
+
+func TestFakesWithClasses(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithFakes(true).Add(Address{})
+
+	typeScriptCode, err := converter.WithIndent("\t").Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export function fakeAddress(overrides?: Partial<Address>): Address {
+	return Object.assign(Object.create(Address.prototype), {
+		duration: 1,
+		text: "text",
+	}, overrides);
+}`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`fakeAddress() instanceof Address`,
+		`fakeAddress({duration: 7}).duration === 7`,
+	})
+}
+
+func TestFakesWithOptions(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithInterface(true).WithFakes(true).WithInlineAnonymousStructs(true).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(NewStruct(PersonWithAnonymousStructs{}).WithFieldOptsByName("Name", TypeOptions{TSFake: "name"})).
+		Add(Drawing{})
+
+	typeScriptCode, err := converter.WithIndent("\t").Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export function fakePersonWithAnonymousStructs(overrides?: Partial<PersonWithAnonymousStructs>): PersonWithAnonymousStructs {
+	return {
+		name: "Jane Doe",
+		meta: {
+			created: "created",
+			tags: [{
+				key: "key",
+			}],
+		},
+		extra: {
+			Note: "Note",
+		},
+		by_label: {"key": {
+			value: 1,
+		}},
+		...overrides,
+	};
+}`)
+	assert.Contains(t, typeScriptCode, `export function fakeDrawing(overrides?: Partial<Drawing>): Drawing {
+	return {
+		main: fakeCircle(),
+		shapes: [fakeCircle()],
+		by_name: {"key": fakeCircle()},
+		...overrides,
+	};
+}`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`fakeDrawing().main.kind === "circle"`,
+		`fakePersonWithAnonymousStructs().meta.tags[0].key === "key"`,
+	})
+}
@@ -0,0 +1,134 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type fixture struct {
+	name  string
+	value interface{}
+}
+
+// AddFixture adds a typescript constant with a Golang value serialized with `encoding/json`, so that the literal is
+// exactly what the API sends. I.e. `AddFixture("Alice", alice)` will be converted to
+// `export const fixtureAlice: Person = {...}` (or `new Person({...})` for classes).
+func (t *TypeScriptify) AddFixture(name string, value interface{}) *TypeScriptify {
+	if typ := valueStructType(value); typ != nil && typ.Name() != "" {
+		t.AddType(typ)
+	}
+	t.fixtures = append(t.fixtures, fixture{name: name, value: value})
+	return t
+}
+
+func (t *TypeScriptify) convertFixtures() (string, error) {
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	var lines []string
+	for _, f := range t.fixtures {
+		t.logf(0, "Converting fixture %s", f.name)
+		byts, err := json.MarshalIndent(f.value, "", t.Indent)
+		if err != nil {
+			return "", fmt.Errorf("fixture %s: %w", f.name, err)
+		}
+		jsn := string(byts)
+		name := "fixture" + upperFirst(f.name)
+
+		typ := reflect.TypeOf(f.value)
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		structType := valueStructType(f.value)
+		switch {
+		case t.Output == DeclarationOutput:
+			fixtureType := "any"
+			if f.value != nil {
+				fixtureType = t.valueTSType(typ)
+			}
+			lines = append(lines, fmt.Sprintf("%sdeclare const %s: %s;", export, name, fixtureType))
+		case structType == nil:
+			lines = append(lines, fmt.Sprintf("%sconst %s = %s;", export, name, jsn))
+		case t.isInterface(structType) || !t.CreateConstructor:
+			if t.Output == JavaScriptOutput {
+				lines = append(lines, jsDoc("", "@type {"+t.valueTSType(typ)+"}")+fmt.Sprintf("%sconst %s = %s;", export, name, jsn))
+				break
+			}
+			if jsonHasNull(byts) {
+				// Nil slices, maps and pointers are `null` in JSON, but not in the typescript types:
+				lines = append(lines, fmt.Sprintf("%sconst %s = %s as unknown as %s;", export, name, jsn, t.valueTSType(typ)))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%sconst %s: %s = %s;", export, name, t.valueTSType(typ), jsn))
+		case typ.Kind() == reflect.Struct:
+			lines = append(lines, fmt.Sprintf("%sconst %s = new %s(%s);", export, name, t.valueTSType(typ), jsn))
+		case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+			className := t.Prefix + t.structName(structType) + t.Suffix
+			if !isStructElem(typ) {
+				return "", fmt.Errorf("fixture %s: only structs, and slices and maps of structs can be instantiated", f.name)
+			}
+			if t.Output == JavaScriptOutput {
+				lines = append(lines, fmt.Sprintf("%sconst %s = %s.map(elem => new %s(elem));", export, name, strings.TrimSpace(jsn), className))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%sconst %s: %s[] = (%s as any[]).map(elem => new %s(elem));", export, name, className, strings.TrimSpace(jsn), className))
+		case typ.Kind() == reflect.Map:
+			className := t.Prefix + t.structName(structType) + t.Suffix
+			if !isStructElem(typ) {
+				return "", fmt.Errorf("fixture %s: only structs, and slices and maps of structs can be instantiated", f.name)
+			}
+			// Like `convertValues(..., true)` in the class constructors:
+			mapType, mapParam := "", "map"
+			if t.Output != JavaScriptOutput {
+				mapType, mapParam = ": "+t.valueTSType(typ), "(map: any)"
+			}
+			lines = append(lines, fmt.Sprintf("%sconst %s%s = (%s => {\n%sObject.keys(map).forEach(key => map[key] = new %s(map[key]));\n%sreturn map;\n})(%s);",
+				export, name, mapType, mapParam, t.Indent, className, t.Indent, strings.TrimSpace(jsn)))
+		default:
+			return "", fmt.Errorf("fixture %s: only structs, and slices and maps of structs can be instantiated", f.name)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// isStructElem returns true if the elements of a slice, array or map are structs (or pointers to structs).
+func isStructElem(typ reflect.Type) bool {
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
+}
+
+// jsonHasNull returns true if a JSON document contains null values.
+func jsonHasNull(byts []byte) bool {
+	var value interface{}
+	if err := json.Unmarshal(byts, &value); err != nil {
+		return false
+	}
+	return hasNull(value)
+}
+
+func hasNull(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		for _, elem := range v {
+			if hasNull(elem) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			if hasNull(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
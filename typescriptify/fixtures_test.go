@@ -0,0 +1,124 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixturesWithInterfaces(t *testing.T) {
+	t.Parallel()
+
+	alice := Address{Duration: 1.5, Text1: "Alice"}
+	converter := New().WithInterface(true).
+		AddFixture("alice", alice).
+		AddFixture("Addresses", []Address{alice}).
+		AddFixture("Count", 3)
+
+	desiredResult := `export interface Address {
+	duration: number;
+	text?: string;
+}
+
+export const fixtureAlice: Address = {
+	"duration": 1.5,
+	"text": "Alice"
+};
+export const fixtureAddresses: Address[] = [
+	{
+		"duration": 1.5,
+		"text": "Alice"
+	}
+];
+export const fixtureCount = 3;`
+	testConverter(t, converter.WithIndent("\t"), true, desiredResult, []string{
+		`fixtureAlice.text === "Alice"`,
+		`fixtureAddresses.length === 1`,
+	})
+}
+
+func TestFixturesWithNulls(t *testing.T) {
+	t.Parallel()
+
+	type Contact struct {
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Address *Address `json:"address"`
+	}
+
+	for _, converter := range []*TypeScriptify{New().WithInterface(true), New().WithConstructor(false)} {
+		typeScriptCode, err := converter.WithIndent("\t").
+			AddFixture("Empty", Contact{Name: "a"}).
+			AddFixture("Full", Contact{Name: "b", Tags: []string{}, Address: &Address{}}).
+			Convert(nil)
+		assert.Nil(t, err)
+		assert.Contains(t, typeScriptCode, `export const fixtureEmpty = {
+	"name": "a",
+	"tags": null,
+	"address": null
+} as unknown as Contact;`)
+		assert.Contains(t, typeScriptCode, `export const fixtureFull: Contact = {`)
+		testTypescriptExpression(t, converter.CreateInterface, typeScriptCode, []string{
+			`fixtureEmpty.tags === null`,
+			`fixtureFull.tags.length === 0`,
+		})
+	}
+}
+
+func TestFixturesWithClasses(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddFixture("Alice", &Address{Duration: 1.5, Text1: "Alice"}).
+		AddFixture("Addresses", []*Address{{Duration: 2}})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const fixtureAlice = new Address({
+    "duration": 1.5,
+    "text": "Alice"
+});
+export const fixtureAddresses: Address[] = ([
+    {
+        "duration": 2
+    }
+] as any[]).map(elem => new Address(elem));`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`fixtureAlice instanceof Address`,
+		`fixtureAddresses[0] instanceof Address`,
+	})
+
+	_, err = New().AddFixture("Nested", [][]Address{}).Convert(nil)
+	assert.NotNil(t, err)
+}
+
+func TestMapFixturesWithClasses(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddFixture("ByName", map[string]*Address{"alice": {Duration: 1.5}})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `export const fixtureByName: {[key: string]: Address} = ((map: any) => {
+    Object.keys(map).forEach(key => map[key] = new Address(map[key]));
+    return map;
+})({
+    "alice": {
+        "duration": 1.5
+    }
+});`)
+	testTypescriptExpression(t, true, typeScriptCode, []string{
+		`fixtureByName["alice"] instanceof Address`,
+		`fixtureByName["alice"].duration === 1.5`,
+	})
+
+	js, err := New().WithOutput(JavaScriptOutput).AddFixture("ByName", map[string]Address{"alice": {Duration: 1.5}}).Convert(nil)
+	assert.Nil(t, err)
+	testJavaScriptExpression(t, js, []string{
+		`fixtureByName["alice"] instanceof Address`,
+	})
+
+	_, err = New().AddFixture("Slices", map[string][]Address{}).Convert(nil)
+	assert.NotNil(t, err)
+}
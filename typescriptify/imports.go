@@ -0,0 +1,219 @@
+package typescriptify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	importRegexp       = regexp.MustCompile(`^import\s+(type\s+)?(.*?)\s*from\s*(['"])([^'"]+)['"]\s*;?$`)
+	importModuleRegexp = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// tsImport is an import statement, imports from the same module are merged.
+type tsImport struct {
+	module    string
+	quote     string
+	typeOnly  bool
+	defaultAs string
+	namespace string
+	named     []string
+	raw       string // unknown syntax, only exact duplicates are removed
+}
+
+// useImport marks the import(s) of a used type, one per line.
+func (t *TypeScriptify) useImport(i string) {
+	for _, line := range strings.Split(i, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			t.usedImports[line] = true
+		}
+	}
+}
+
+// convertImports returns the custom imports (in the order they were added), and the imports of the used types (sorted
+// by module), merged by module.
+func (t *TypeScriptify) convertImports() string {
+	var used []string
+	for i := range t.usedImports {
+		used = append(used, i)
+	}
+	sort.Strings(used)
+
+	imports := mergeImports(nil, t.customImports)
+	custom := len(imports)
+	imports = mergeImports(imports, used)
+	sorted := imports[custom:]
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].moduleName() != sorted[j].moduleName() {
+			return sorted[i].moduleName() < sorted[j].moduleName()
+		}
+		return sorted[i].String() < sorted[j].String()
+	})
+
+	result := ""
+	for _, imp := range imports {
+		if t.Output == JavaScriptOutput && imp.typeOnly {
+			result += imp.jsTypedefs()
+			continue
+		}
+		result += imp.String() + "\n"
+	}
+	return result
+}
+
+func mergeImports(imports []*tsImport, lines []string) []*tsImport {
+	for _, line := range lines {
+		parsed := parseImport(line)
+		if parsed == nil {
+			parsed = &tsImport{raw: line}
+		}
+		merged := false
+		for _, imp := range imports {
+			if imp.merge(parsed) {
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			imports = append(imports, parsed)
+		}
+	}
+	return imports
+}
+
+// parseImport parses `import X, { A, B as C } from 'module'` and `import * as X from 'module'`, nil if the statement
+// can't be merged.
+func parseImport(line string) *tsImport {
+	groups := importRegexp.FindStringSubmatch(strings.TrimSpace(line))
+	if groups == nil {
+		return nil
+	}
+	imp := &tsImport{typeOnly: groups[1] != "", quote: groups[3], module: groups[4]}
+	clause := strings.TrimSpace(groups[2])
+	if start := strings.Index(clause, "{"); start >= 0 {
+		end := strings.Index(clause, "}")
+		if end < start {
+			return nil
+		}
+		for _, name := range strings.Split(clause[start+1:end], ",") {
+			if name = strings.Join(strings.Fields(name), " "); name != "" && !containsString(imp.named, name) {
+				imp.named = append(imp.named, name)
+			}
+		}
+		clause = strings.TrimSpace(clause[:start] + clause[end+1:])
+	}
+	for _, part := range strings.Split(clause, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "*"):
+			fields := strings.Fields(part)
+			if len(fields) != 3 || fields[1] != "as" {
+				return nil
+			}
+			imp.namespace = fields[2]
+		default:
+			if imp.defaultAs != "" {
+				return nil
+			}
+			imp.defaultAs = part
+		}
+	}
+	if imp.namespace != "" && len(imp.named) > 0 {
+		return nil
+	}
+	return imp
+}
+
+// merge merges other into imp, returns false if they can't be merged.
+func (imp *tsImport) merge(other *tsImport) bool {
+	if imp.raw != "" || other.raw != "" {
+		return imp.raw == other.raw
+	}
+	if imp.module != other.module || imp.typeOnly != other.typeOnly {
+		return false
+	}
+	if other.defaultAs != "" && imp.defaultAs != "" && other.defaultAs != imp.defaultAs {
+		return false
+	}
+	if other.namespace != "" && imp.namespace != "" && other.namespace != imp.namespace {
+		return false
+	}
+	if (imp.namespace != "" || other.namespace != "") && (len(imp.named) > 0 || len(other.named) > 0) {
+		return false
+	}
+	if other.defaultAs != "" {
+		imp.defaultAs = other.defaultAs
+	}
+	if other.namespace != "" {
+		imp.namespace = other.namespace
+	}
+	for _, name := range other.named {
+		if !containsString(imp.named, name) {
+			imp.named = append(imp.named, name)
+		}
+	}
+	return true
+}
+
+func (imp tsImport) moduleName() string {
+	if imp.raw != "" {
+		if groups := importModuleRegexp.FindStringSubmatch(imp.raw); groups != nil {
+			return groups[1]
+		}
+	}
+	return imp.module
+}
+
+func (imp tsImport) String() string {
+	if imp.raw != "" {
+		return imp.raw
+	}
+	var clause []string
+	if imp.defaultAs != "" {
+		clause = append(clause, imp.defaultAs)
+	}
+	if imp.namespace != "" {
+		clause = append(clause, "* as "+imp.namespace)
+	}
+	if len(imp.named) > 0 {
+		clause = append(clause, "{ "+strings.Join(imp.named, ", ")+" }")
+	}
+	result := "import "
+	if imp.typeOnly {
+		result += "type "
+	}
+	return result + strings.Join(clause, ", ") + " from " + imp.quote + imp.module + imp.quote + ";"
+}
+
+// jsTypedefs returns the `@typedef`s replacing a type-only import in javascript.
+func (imp tsImport) jsTypedefs() string {
+	var names []string
+	if imp.defaultAs != "" {
+		names = append(names, "default as "+imp.defaultAs)
+	}
+	names = append(names, imp.named...)
+	result := ""
+	if imp.namespace != "" {
+		result += jsDoc("", fmt.Sprintf("@typedef {import(%s%s%s)} %s", imp.quote, imp.module, imp.quote, imp.namespace))
+	}
+	for _, name := range names {
+		imported, local := name, name
+		if parts := strings.SplitN(name, " as ", 2); len(parts) == 2 {
+			imported, local = parts[0], parts[1]
+		}
+		result += jsDoc("", fmt.Sprintf("@typedef {import(%s%s%s).%s} %s", imp.quote, imp.module, imp.quote, imported, local))
+	}
+	return result
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
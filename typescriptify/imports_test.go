@@ -0,0 +1,59 @@
+package typescriptify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsedImports(t *testing.T) {
+	t.Parallel()
+
+	type Money float64
+	type Invoice struct {
+		Total    Money     `json:"total"`
+		Discount *Money    `json:"discount"`
+		Issued   time.Time `json:"issued"`
+	}
+
+	converter := New().
+		ManageType(Money(0), TypeOptions{TSType: "Decimal", TSTransform: "new Decimal(__VALUE__)", Import: "import Decimal from 'decimal.js'"}).
+		ManageType(time.Time{}, TypeOptions{TSType: "DateTime", TSTransform: "DateTime.fromISO(__VALUE__)", Import: "import { DateTime } from 'luxon';"}).
+		ManageType(time.Duration(0), TypeOptions{TSType: "Duration", Import: "import { Duration } from 'luxon';"}).
+		Add(Invoice{})
+	converter.AddImport("import { Interval } from 'luxon';")
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	imports := ts[:strings.Index(ts, "export")]
+	assert.Equal(t, `import { Interval, DateTime } from 'luxon';
+import Decimal from 'decimal.js';
+`, strings.TrimSpace(imports)+"\n")
+}
+
+func TestMergeImports(t *testing.T) {
+	t.Parallel()
+
+	converter := New()
+	converter.usedImports = map[string]bool{}
+	for _, i := range []string{
+		"import Decimal from 'decimal.js'",
+		"import { Decimal as D } from \"decimal.js\";",
+		"import * as lodash from 'lodash';",
+		"import { debounce } from 'lodash';",
+		"import type { Point } from 'geojson';",
+		"import type { Polygon } from 'geojson';",
+		"import 'reflect-metadata';",
+		"import 'reflect-metadata';",
+	} {
+		converter.useImport(i)
+	}
+	assert.Equal(t, `import Decimal, { Decimal as D } from 'decimal.js';
+import type { Point, Polygon } from 'geojson';
+import * as lodash from 'lodash';
+import { debounce } from 'lodash';
+import 'reflect-metadata';
+`, converter.convertImports())
+}
@@ -0,0 +1,91 @@
+package typescriptify
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// FieldNamer converts a field name into another naming convention.
+type FieldNamer func(name string) string
+
+var (
+	// CamelCase converts `FirstName` or `first_name` to `firstName`.
+	CamelCase FieldNamer = func(name string) string {
+		words := splitWords(name)
+		for n := range words {
+			if n == 0 {
+				words[n] = strings.ToLower(words[n])
+			} else {
+				words[n] = upperFirst(words[n])
+			}
+		}
+		return strings.Join(words, "")
+	}
+	// SnakeCase converts `FirstName` to `first_name`.
+	SnakeCase FieldNamer = func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	}
+	// KebabCase converts `FirstName` to `first-name`.
+	KebabCase FieldNamer = func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name), "-"))
+	}
+)
+
+func upperFirst(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// splitWords splits `HTTPServerID`, `first_name` or `first-name` into words.
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		prev := runes[i-1]
+		newWord := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if newWord {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+var tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsPropertyName returns the property name as it must be written in a class/interface definition.
+func tsPropertyName(name string) string {
+	if tsIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return `"` + name + `"`
+}
+
+// tsPropertyAccess returns the property accessor (`.name` or `["na-me"]`).
+func tsPropertyAccess(name string) string {
+	if tsIdentifierRegexp.MatchString(name) {
+		return "." + name
+	}
+	return `["` + name + `"]`
+}
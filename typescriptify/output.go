@@ -0,0 +1,204 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Output is the kind of generated code.
+type Output int
+
+const (
+	// TypeScriptOutput generates typescript (default).
+	TypeScriptOutput Output = iota
+	// DeclarationOutput generates typescript declarations (a `.d.ts` file).
+	DeclarationOutput
+	// JavaScriptOutput generates an ES module with JSDoc type annotations. ConvertToFile also writes the declarations into
+	// a `.d.ts` file next to the javascript file.
+	JavaScriptOutput
+)
+
+const jsConvertValuesFunc = `convertValues(a, classs, asMap = false) {
+	if (!a) {
+		return a;
+	}
+	if (Array.isArray(a)) {
+		return a.map(elem => this.convertValues(elem, classs));
+	} else if ("object" === typeof a) {
+		if (asMap) {
+			for (const key of Object.keys(a)) {
+				a[key] = new classs(a[key]);
+			}
+			return a;
+		}
+		return new classs(a);
+	}
+	return a;
+}`
+
+// WithOutput sets the kind of generated code, see TypeScriptOutput, DeclarationOutput and JavaScriptOutput.
+func (t *TypeScriptify) WithOutput(o Output) *TypeScriptify {
+	t.Output = o
+	return t
+}
+
+// declarationFileName returns the `.d.ts` file for a javascript file (`.d.mts` for `.mjs` files).
+func declarationFileName(fileName string) string {
+	if strings.HasSuffix(fileName, ".mjs") {
+		return strings.TrimSuffix(fileName, ".mjs") + ".d.mts"
+	}
+	return strings.TrimSuffix(fileName, ".js") + ".d.ts"
+}
+
+// declare returns the `declare ` keyword for declaration output.
+func (t *TypeScriptify) declare() string {
+	if t.Output == DeclarationOutput {
+		return "declare "
+	}
+	return ""
+}
+
+// tsProperty is a converted struct field.
+type tsProperty struct {
+	name     string
+	optional bool
+	readonly bool
+	tsType   string
+	doc      string
+}
+
+// jsDoc returns a JSDoc comment, on one line if possible.
+func jsDoc(indent string, lines ...string) string {
+	if len(lines) == 1 && !strings.Contains(lines[0], "\n") {
+		return indent + "/** " + lines[0] + " */\n"
+	}
+	result := indent + "/**\n"
+	for _, line := range lines {
+		for _, l := range strings.Split(line, "\n") {
+			result += indent + " * " + l + "\n"
+		}
+	}
+	return result + indent + " */\n"
+}
+
+// typeAlias returns a type alias, a `@typedef` in javascript.
+func (t *TypeScriptify) typeAlias(name, typ string) string {
+	if t.Output == JavaScriptOutput {
+		return jsDoc("", fmt.Sprintf("@typedef {%s} %s", typ, name))
+	}
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	return fmt.Sprintf("%stype %s = %s;\n", export, name, typ)
+}
+
+// convertJSType returns the javascript class, or the `@typedef` of an interface.
+func (t *TypeScriptify) convertJSType(typeOf reflect.Type, entityName string, builder *typeScriptClassBuilder, customCode map[string]string) string {
+	if t.isInterface(typeOf) {
+		lines := []string{fmt.Sprintf("@typedef {Object} %s", entityName)}
+		for _, prop := range builder.properties {
+			name := tsPropertyName(prop.name)
+			if prop.optional {
+				name = "[" + name + "]"
+			}
+			line := fmt.Sprintf("@property {%s} %s", prop.tsType, name)
+			if prop.doc != "" {
+				line += " " + prop.doc
+			}
+			lines = append(lines, line)
+		}
+		result := strings.TrimSuffix(jsDoc("", lines...), "\n")
+		// Custom code blocks are kept, even if there is nothing to add them to:
+		if code := t.customCodeBlock(customCode, entityName, ""); code != "" {
+			result += "\n" + strings.TrimSuffix(code, "\n")
+		}
+		return result
+	}
+
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	result := fmt.Sprintf("%sclass %s {\n", export, entityName)
+	for n, prop := range builder.properties {
+		var lines []string
+		if prop.doc != "" {
+			lines = append(lines, prop.doc)
+		}
+		if prop.readonly {
+			lines = append(lines, "@readonly")
+		}
+		typ := prop.tsType
+		if prop.optional {
+			typ += " | undefined"
+		}
+		lines = append(lines, "@type {"+typ+"}")
+		if n > 0 {
+			result += "\n"
+		}
+		result += jsDoc(t.Indent, lines...)
+		result += t.Indent + tsPropertyName(prop.name) + ";\n"
+	}
+
+	constructorBody := strings.Join(builder.constructorBody, "\n")
+	needsConvertValue := strings.Contains(constructorBody, "this.convertValues")
+	if t.CreateFromMethod {
+		result += "\n" + jsDoc(t.Indent, "@param {any} [source]", "@returns {"+entityName+"}")
+		result += fmt.Sprintf("%sstatic createFrom(source = {}) {\n", t.Indent)
+		result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
+		result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+	if t.CreateConstructor {
+		result += "\n" + jsDoc(t.Indent, "@param {any} [source]")
+		result += fmt.Sprintf("%sconstructor(source = {}) {\n", t.Indent)
+		result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
+		result += constructorBody + "\n"
+		if t.Readonly {
+			result += t.Indent + t.Indent + "Object.freeze(this);\n"
+		}
+		result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+	if needsConvertValue && (t.CreateConstructor || t.CreateFromMethod) {
+		result += "\n" + indentLinesWith(strings.ReplaceAll(jsConvertValuesFunc, "\t", t.Indent), t.Indent) + "\n"
+	}
+	if len(builder.serializers) > 0 {
+		result += fmt.Sprintf("\n%stoJSON() {\n", t.Indent)
+		result += t.Indent + t.Indent + "return {\n"
+		result += t.Indent + t.Indent + t.Indent + "...this,\n"
+		result += strings.Join(builder.serializers, "\n") + "\n"
+		result += t.Indent + t.Indent + "};\n"
+		result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+
+	if code := t.structCustomCode(typeOf); code != "" {
+		result += "\n" + code + "\n"
+	}
+
+	result += t.customCodeBlock(customCode, entityName, t.Indent)
+
+	result += "}"
+	return result
+}
+
+// convertJSEnum returns a frozen object with the enum values.
+func (t *TypeScriptify) convertJSEnum(entityName string, elements []enumElement, customCode map[string]string) string {
+	valueType := "number"
+	if len(elements) > 0 && reflect.ValueOf(elements[0].value).Kind() == reflect.String {
+		valueType = "string"
+	}
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	result := jsDoc("", "@enum {"+valueType+"}")
+	result += fmt.Sprintf("%sconst %s = Object.freeze({\n", export, entityName)
+	for _, val := range elements {
+		result += fmt.Sprintf("%s%s: %#v,\n", t.Indent, tsPropertyName(val.name), val.value)
+	}
+	result += t.customCodeBlock(customCode, entityName, t.Indent)
+	result += "});"
+	return result
+}
@@ -0,0 +1,184 @@
+package typescriptify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclarationOutput(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithOutput(DeclarationOutput).WithFakes(true).
+		AddEnum(allWeekdaysV2).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(Address{}).
+		AddConstant("MAX_ADDRESSES", 10).
+		AddFixture("home", Address{Duration: 1})
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	assert.Contains(t, ts, "export declare enum Weekday {\n\tSUNDAY = 0,")
+	assert.Contains(t, ts, `export declare class Circle {
+	kind: "circle";
+	radius: number;
+
+	constructor(source?: any);
+}`)
+	assert.Contains(t, ts, "export type Shape = Circle | Square;\nexport declare function createShape(source?: any): Shape;")
+	assert.Contains(t, ts, "export declare function fakeAddress(overrides?: Partial<Address>): Address;")
+	assert.Contains(t, ts, "export declare const MAX_ADDRESSES: number;")
+	assert.Contains(t, ts, "export declare const fixtureHome: Address;")
+	assert.NotContains(t, ts, "source[")
+
+	ts, err = New().WithOutput(DeclarationOutput).WithInterface(true).Add(Address{}).Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, "export interface Address {\n    duration: number;\n    text?: string;\n}")
+}
+
+func TestJavaScriptOutput(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithOutput(JavaScriptOutput).
+		AddEnum(allWeekdaysV2).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(Person{}).
+		AddConstant("MAX_ADDRESSES", 10)
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+
+	assert.Contains(t, ts, `/** @enum {number} */
+export const Weekday = Object.freeze({
+	SUNDAY: 0,`)
+	assert.Contains(t, ts, `export class Address {
+	/** @type {number} */
+	duration;
+
+	/** @type {string | undefined} */
+	text;
+
+	/** @param {any} [source] */
+	constructor(source = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.duration = source["duration"];
+		this.text = source["text"];
+	}
+}`)
+	assert.Contains(t, ts, "\n\tconvertValues(a, classs, asMap = false) {\n")
+	assert.Contains(t, ts, `/** @typedef {Circle | Square} Shape */
+/**
+ * @param {any} [source]
+ * @returns {Shape}
+ */
+export function createShape(source = {}) {`)
+	assert.Contains(t, ts, "/** @type {number} */\nexport const MAX_ADDRESSES = 10;")
+	assert.NotContains(t, ts, ": any")
+	testJavaScriptExpression(t, ts, []string{
+		`new Person({"name": "Jane", "address": {"duration": 1}}).address instanceof Address`,
+		`createShape({"kind": "circle", "radius": 2}) instanceof Circle`,
+		`Weekday.MONDAY === 1`,
+	})
+
+	ts, err = New().WithOutput(JavaScriptOutput).WithInterface(true).Add(Address{}).Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, `/**
+ * @typedef {Object} Address
+ * @property {number} duration
+ * @property {string} [text]
+ */`)
+}
+
+func TestJavaScriptTypeImports(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithOutput(JavaScriptOutput).Add(Dummy{})
+	converter.AddImport("import type { Point, Polygon as Area } from 'geojson';")
+	converter.AddImport("import Decimal from 'decimal.js';")
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, `/** @typedef {import('geojson').Point} Point */
+/** @typedef {import('geojson').Polygon} Area */
+import Decimal from 'decimal.js';
+`)
+}
+
+func TestOutputWithEndpoints(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().WithOutput(JavaScriptOutput).AddEndpoint("GET", "/addresses", nil, []Address{}).Convert(nil)
+	assert.NotNil(t, err)
+}
+
+func TestJavaScriptOutputToFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	converter := New().WithBackupDir("").WithOutput(JavaScriptOutput).Add(Address{})
+
+	changed, err := converter.ConvertToFileIfChanged(filepath.Join(dir, "models.js"))
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	js, err := os.ReadFile(filepath.Join(dir, "models.js"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(js), "export class Address {")
+	dts, err := os.ReadFile(filepath.Join(dir, "models.d.ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(dts), "export declare class Address {")
+
+	changed, err = converter.ConvertToFileIfChanged(filepath.Join(dir, "models.js"))
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	assert.Equal(t, "models.d.mts", declarationFileName("models.mjs"))
+}
+
+func TestJavaScriptStructCustomCode(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithOutput(JavaScriptOutput).
+		Add(NewStruct(FullNamePerson{}).
+			WithCustomCode("greet(): string {\n\treturn \"Hello \" + this.first;\n}").
+			WithCustomJSCode("greet() {\n\treturn \"Hello \" + this.first;\n}"))
+	js, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.NotContains(t, js, "): string")
+	testJavaScriptExpression(t, js, []string{
+		`new FullNamePerson({"first": "Jane", "last": "Doe"}).fullName === "Jane Doe"`,
+		`new FullNamePerson({"first": "Jane"}).greet() === "Hello Jane"`,
+	})
+}
+
+// testJavaScriptExpression runs the generated ES module (with node), and checks the expressions.
+func testJavaScriptExpression(t *testing.T, baseScript string, jsExpressions []string) {
+	f, err := os.CreateTemp(t.TempDir(), "*.mjs")
+	assert.Nil(t, err)
+	_, _ = f.WriteString(baseScript + "\n")
+	for n, expr := range jsExpressions {
+		_, _ = f.WriteString(fmt.Sprintf("if (!(%s)) { throw new Error(\"#%d failed\"); }\n", expr, 1+n))
+	}
+	assert.Nil(t, f.Close())
+
+	byts, err := exec.Command("node", f.Name()).CombinedOutput()
+	assert.Nil(t, err, string(byts))
+}
+
+func TestJavaScriptPropertyNames(t *testing.T) {
+	t.Parallel()
+
+	type Account struct {
+		FirstName string  `json:"first_name"`
+		Nickname  *string `json:"nick_name"`
+	}
+
+	js, err := New().WithOutput(JavaScriptOutput).WithTSPropertyNamer(CamelCase).Add(Account{}).Convert(nil)
+	assert.Nil(t, err)
+	testJavaScriptExpression(t, js, []string{
+		`new Account({"first_name": "Jane"}).firstName === "Jane"`,
+		`JSON.stringify(new Account({"first_name": "Jane", "nick_name": "J"})) === '{"first_name":"Jane","nick_name":"J"}'`,
+		`JSON.stringify(new Account({"first_name": "Jane"})) === '{"first_name":"Jane"}'`,
+	})
+}
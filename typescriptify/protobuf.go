@@ -0,0 +1,213 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	protobufTag      = "protobuf"
+	protobufOneofTag = "protobuf_oneof"
+	protobufKnownPkg = "google.golang.org/protobuf/types/known/"
+)
+
+// protobufKnownTypes are the protojson representations of well-known types.
+var protobufKnownTypes = map[string]string{
+	"timestamppb.Timestamp":  "string",
+	"durationpb.Duration":    "string",
+	"fieldmaskpb.FieldMask":  "string",
+	"emptypb.Empty":          "{}",
+	"structpb.Struct":        "{[key: string]: any}",
+	"structpb.Value":         "any",
+	"structpb.ListValue":     "any[]",
+	"anypb.Any":              `{"@type": string, [key: string]: any}`,
+	"wrapperspb.DoubleValue": "number | null",
+	"wrapperspb.FloatValue":  "number | null",
+	"wrapperspb.Int32Value":  "number | null",
+	"wrapperspb.UInt32Value": "number | null",
+	"wrapperspb.Int64Value":  "string | null",
+	"wrapperspb.UInt64Value": "string | null",
+	"wrapperspb.BoolValue":   "boolean | null",
+	"wrapperspb.StringValue": "string | null",
+	"wrapperspb.BytesValue":  "string | null",
+	"structpb.NullValue":     "null",
+}
+
+// ProtobufTypeMapper maps protobuf well-known types (`timestamppb.Timestamp`, `durationpb.Duration`, wrappers, ...),
+// 64-bit integers and bytes (which are strings in protojson), and enums not added with AddProtoEnum (enum names are
+// strings). Used by default in protobuf mode.
+var ProtobufTypeMapper TypeMapper = TypeMapperFunc(mapProtobufType)
+
+func mapProtobufType(typ reflect.Type, field FieldContext) (TypeMapping, bool) {
+	tag, tagged := field.Field.Tag.Lookup(protobufTag)
+	if !tagged {
+		return TypeMapping{}, false
+	}
+	if tagOption(strings.Split(tag, ","), []string{"enum"}) != "" {
+		if typ.Kind() == reflect.Slice {
+			return TypeMapping{TSType: "string[]"}, true
+		}
+		return TypeMapping{TSType: "string"}, true
+	}
+	tsType := protobufTSType(typ)
+	if tsType == "" {
+		return TypeMapping{}, false
+	}
+	return TypeMapping{TSType: tsType}, true
+}
+
+// protobufTSType returns the protojson type of well-known types, 64-bit integers and bytes (also in repeated fields
+// and maps), empty for other types.
+func protobufTSType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if strings.HasPrefix(typ.PkgPath(), protobufKnownPkg) {
+		return protobufKnownType(typ.PkgPath(), typ.Name())
+	}
+	switch typ.Kind() {
+	case reflect.Int64, reflect.Uint64:
+		return "string"
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		if elem := protobufTSType(typ.Elem()); elem != "" {
+			if strings.Contains(elem, " ") {
+				elem = "(" + elem + ")"
+			}
+			return elem + "[]"
+		}
+	case reflect.Map:
+		if elem := protobufTSType(typ.Elem()); elem != "" {
+			return fmt.Sprintf("{[key: string]: %s}", elem)
+		}
+	}
+	return ""
+}
+
+func protobufKnownType(pkgPath, name string) string {
+	return protobufKnownTypes[strings.TrimPrefix(pkgPath, protobufKnownPkg)+"."+name]
+}
+
+// WithProtobuf converts structs generated by protoc-gen-go as they are serialized with protojson: field names from the
+// `protobuf` tag, well-known types (see ProtobufTypeMapper), oneof fields, and enums added with AddProtoEnum.
+func (t *TypeScriptify) WithProtobuf(b bool) *TypeScriptify {
+	t.Protobuf = b
+	return t
+}
+
+// AddProtoEnum adds a protobuf enum, names is the generated `_name` map, i.e.:
+//
+//	converter.AddProtoEnum(pb.Status(0), pb.Status_name)
+//
+// Protojson serializes enums by name, so the typescript enum values are strings.
+func (t *TypeScriptify) AddProtoEnum(enum interface{}, names interface{}) *TypeScriptify {
+	typ := reflect.TypeOf(enum)
+	namesValue := reflect.ValueOf(names)
+	if namesValue.Kind() != reflect.Map || namesValue.Type().Elem().Kind() != reflect.String {
+		panic(fmt.Sprintf("names for %s must be a map of names (i.e. %s_name), got %T", typ.String(), typ.Name(), names))
+	}
+	if !namesValue.Type().Key().ConvertibleTo(typ) {
+		panic(fmt.Sprintf("%s can't be converted to %s", namesValue.Type().Key().String(), typ.String()))
+	}
+
+	var elements []enumElement
+	iter := namesValue.MapRange()
+	for iter.Next() {
+		name := iter.Value().String()
+		elements = append(elements, enumElement{name: name, value: name, goValue: iter.Key().Convert(typ).Interface()})
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return reflect.ValueOf(elements[i].goValue).Int() < reflect.ValueOf(elements[j].goValue).Int()
+	})
+
+	if t.enums == nil {
+		t.enums = map[reflect.Type][]enumElement{}
+	}
+	t.enums[typ] = elements
+	t.enumTypes = append(t.enumTypes, EnumType{Type: typ})
+	return t
+}
+
+// protobufOneofMembers returns the wrapper structs of a oneof field (i.e. `Person_Email` for `isPerson_Contact`), from
+// the `XXX_OneofWrappers` method (older protoc-gen-go versions), and from the added structs.
+func (t *TypeScriptify) protobufOneofMembers(parent reflect.Type, field reflect.StructField) []reflect.Type {
+	var members []reflect.Type
+	add := func(typ reflect.Type) {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || !reflect.PtrTo(typ).Implements(field.Type) {
+			return
+		}
+		for _, member := range members {
+			if member == typ {
+				return
+			}
+		}
+		members = append(members, typ)
+	}
+
+	if method := reflect.New(parent).MethodByName("XXX_OneofWrappers"); method.IsValid() && method.Type().NumIn() == 0 {
+		if out := method.Call(nil); len(out) == 1 && out[0].Kind() == reflect.Slice {
+			for i := 0; i < out[0].Len(); i++ {
+				if wrapper := out[0].Index(i); !wrapper.IsNil() {
+					add(wrapper.Elem().Type())
+				}
+			}
+		}
+	}
+	for _, strct := range t.structTypes {
+		if isProtobufOneofWrapper(strct.Type) {
+			add(strct.Type)
+		}
+	}
+	return members
+}
+
+// isProtobufOneofWrapper returns true for the generated oneof wrapper structs (with one `protobuf:"...,oneof"` field).
+func isProtobufOneofWrapper(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ.NumField() != 1 {
+		return false
+	}
+	for _, opt := range strings.Split(typ.Field(0).Tag.Get(protobufTag), ",") {
+		if opt == "oneof" {
+			return true
+		}
+	}
+	return false
+}
+
+// convertProtobufOneofs returns the union types of the oneof fields of a struct, i.e. `export type PersonContact =
+// Required<Pick<Person, "email">> | Required<Pick<Person, "phone">>;`.
+func (t *TypeScriptify) convertProtobufOneofs(typeOf reflect.Type) string {
+	if !t.Protobuf {
+		return ""
+	}
+	entityName := t.Prefix + t.structName(typeOf) + t.Suffix
+	dialect := t.tagDialect(typeOf)
+	result := ""
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		oneof := field.Tag.Get(protobufOneofTag)
+		if oneof == "" || field.Type.Kind() != reflect.Interface {
+			continue
+		}
+		var alternatives []string
+		for _, member := range t.protobufOneofMembers(typeOf, field) {
+			for _, deepField := range t.deepFields(member, dialect, field.Name+".") {
+				if _, fieldName, _ := t.fieldNames(typeOf, deepField, dialect); fieldName != "" {
+					alternatives = append(alternatives, fmt.Sprintf("Required<Pick<%s, %q>>", entityName, strings.TrimSuffix(fieldName, "?")))
+				}
+			}
+		}
+		if len(alternatives) == 0 {
+			continue
+		}
+		result += "\n" + strings.TrimSuffix(t.typeAlias(t.Prefix+t.structName(typeOf)+upperFirst(CamelCase(oneof))+t.Suffix, strings.Join(alternatives, " | ")), "\n")
+	}
+	return result
+}
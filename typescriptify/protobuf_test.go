@@ -0,0 +1,173 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Structs like the ones generated by protoc-gen-go:
+
+type PbStatus int32
+
+const (
+	PbStatus_STATUS_UNKNOWN PbStatus = 0
+	PbStatus_STATUS_ACTIVE  PbStatus = 1
+	PbStatus_STATUS_BANNED  PbStatus = 5
+)
+
+var PbStatus_name = map[int32]string{
+	0: "STATUS_UNKNOWN",
+	1: "STATUS_ACTIVE",
+	5: "STATUS_BANNED",
+}
+
+type pbMessageState struct{ atomicMessageInfo *int }
+
+type PbAddress struct {
+	state         pbMessageState
+	sizeCache     int32
+	unknownFields []byte
+
+	StreetName string `protobuf:"bytes,1,opt,name=street_name,json=streetName,proto3" json:"street_name,omitempty"`
+}
+
+type PbUser struct {
+	state         pbMessageState
+	sizeCache     int32
+	unknownFields []byte
+
+	Id        int64      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName string     `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	Status    PbStatus   `protobuf:"varint,3,opt,name=status,proto3,enum=test.Status" json:"status,omitempty"`
+	Roles     []PbStatus `protobuf:"varint,4,rep,packed,name=roles,proto3,enum=test.Status" json:"roles,omitempty"`
+	Avatar    []byte     `protobuf:"bytes,5,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Address   *PbAddress `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	// Types that are assignable to Contact:
+	//
+	//	*PbUser_Email
+	//	*PbUser_HomeAddress
+	Contact isPbUser_Contact `protobuf_oneof:"contact"`
+}
+
+type isPbUser_Contact interface {
+	isPbUser_Contact()
+}
+
+type PbUser_Email struct {
+	Email string `protobuf:"bytes,7,opt,name=email,proto3,oneof"`
+}
+
+type PbUser_HomeAddress struct {
+	HomeAddress *PbAddress `protobuf:"bytes,8,opt,name=home_address,json=homeAddress,proto3,oneof"`
+}
+
+func (*PbUser_Email) isPbUser_Contact() {}
+
+func (*PbUser_HomeAddress) isPbUser_Contact() {}
+
+func (*PbUser) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*PbUser_Email)(nil),
+		(*PbUser_HomeAddress)(nil),
+	}
+}
+
+func TestProtobuf(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithProtobuf(true).
+		AddProtoEnum(PbStatus(0), PbStatus_name).
+		Add(PbUser{})
+
+	desiredResult := `export enum PbStatus {
+	STATUS_UNKNOWN = "STATUS_UNKNOWN",
+	STATUS_ACTIVE = "STATUS_ACTIVE",
+	STATUS_BANNED = "STATUS_BANNED",
+}
+export class PbAddress {
+	streetName?: string;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.streetName = source["streetName"];
+	}
+}
+export class PbUser {
+	id?: string;
+	firstName?: string;
+	status?: PbStatus;
+	roles?: string[];
+	avatar?: string;
+	address?: PbAddress;
+	email?: string;
+	homeAddress?: PbAddress;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.id = source["id"];
+		this.firstName = source["firstName"];
+		this.status = source["status"];
+		this.roles = source["roles"];
+		this.avatar = source["avatar"];
+		this.address = this.convertValues(source["address"], PbAddress);
+		this.email = source["email"];
+		this.homeAddress = this.convertValues(source["homeAddress"], PbAddress);
+	}
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		if (!a) {
+			return a;
+		}
+		if (Array.isArray(a)) {
+			return (a as any[]).map(elem => this.convertValues(elem, classs));
+		} else if ("object" === typeof a) {
+			if (asMap) {
+				for (const key of Object.keys(a)) {
+					a[key] = new classs(a[key]);
+				}
+				return a;
+			}
+			return new classs(a);
+		}
+		return a;
+	}
+}
+export type PbUserContact = Required<Pick<PbUser, "email">> | Required<Pick<PbUser, "homeAddress">>;`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new PbUser({"id": "12", "homeAddress": {"streetName": "Main"}}).homeAddress?.streetName === "Main"`,
+		`new PbUser({"status": "STATUS_BANNED"}).status === PbStatus.STATUS_BANNED`,
+	})
+}
+
+func TestProtobufConstantsAndWrappers(t *testing.T) {
+	t.Parallel()
+
+	// Without XXX_OneofWrappers (newer protoc-gen-go), the wrappers are found in the added structs:
+	type pbPet struct {
+		Name  string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+		Owner isPbUser_Contact `protobuf_oneof:"owner"`
+	}
+	converter := New().WithProtobuf(true).WithInterface(true).
+		AddProtoEnum(PbStatus(0), PbStatus_name).
+		Add(pbPet{}).
+		Add(PbUser_Email{}).
+		AddConstant("BANNED", PbStatus_STATUS_BANNED).
+		AddConstant("PET", pbPet{Name: "Rex", Owner: &PbUser_Email{Email: "jane@example.com"}})
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, "export interface pbPet {\n    name?: string;\n    email?: string;\n}")
+	assert.Contains(t, ts, `export type pbPetOwner = Required<Pick<pbPet, "email">>;`)
+	assert.NotContains(t, ts, "PbUser_Email")
+	assert.Contains(t, ts, "export const BANNED = PbStatus.STATUS_BANNED;")
+	assert.Contains(t, ts, `email: "jane@example.com"`)
+}
+
+func TestProtobufKnownTypes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "string", protobufKnownType("google.golang.org/protobuf/types/known/timestamppb", "Timestamp"))
+	assert.Equal(t, "number | null", protobufKnownType("google.golang.org/protobuf/types/known/wrapperspb", "Int32Value"))
+	assert.Equal(t, "{[key: string]: any}", protobufKnownType("google.golang.org/protobuf/types/known/structpb", "Struct"))
+	assert.Equal(t, "", protobufKnownType("google.golang.org/protobuf/types/known/structpb", "Unknown"))
+}
@@ -0,0 +1,149 @@
+package typescriptify
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TagDialect describes how a struct tag (json, yaml, bson, ...) is parsed into field names.
+type TagDialect struct {
+	// Tag is the struct tag key, i.e. "yaml".
+	Tag string
+	// DefaultName returns the field name used when the field has no tag.
+	DefaultName func(field reflect.StructField) string
+	// EmptyNameFallback uses DefaultName also for tags with options but no name (i.e. `yaml:",omitempty"`).
+	// If false, such fields are ignored.
+	EmptyNameFallback bool
+	// InlineOption is the tag option which flattens a struct field into the parent struct, empty if not supported.
+	InlineOption string
+	// InlineEmbedded flattens embedded structs without a tag name into the parent struct.
+	InlineEmbedded bool
+	// NameOptions are tag options with the field name (i.e. `json=firstName`), the first one found is used. If empty,
+	// the name is the first part of the tag.
+	NameOptions []string
+	// OmitEmpty makes all tagged fields optional (empty values are always omitted).
+	OmitEmpty bool
+}
+
+func goFieldName(field reflect.StructField) string {
+	return field.Name
+}
+
+func lowercaseFieldName(field reflect.StructField) string {
+	return strings.ToLower(field.Name)
+}
+
+var (
+	// JSONTags follows `encoding/json` rules, except that fields with options but no name (i.e. `json:",omitempty"`)
+	// are ignored (as in previous versions, set EmptyNameFallback for `encoding/json` behavior).
+	JSONTags = TagDialect{
+		Tag:            "json",
+		DefaultName:    goFieldName,
+		InlineEmbedded: true,
+	}
+	// YAMLTags follows `gopkg.in/yaml` rules (supports `inline`, `flow` is ignored).
+	YAMLTags = TagDialect{
+		Tag:               "yaml",
+		DefaultName:       lowercaseFieldName,
+		EmptyNameFallback: true,
+		InlineOption:      "inline",
+	}
+	// BSONTags follows the MongoDB driver rules (supports `inline`, `minsize` and `truncate` are ignored).
+	BSONTags = TagDialect{
+		Tag:               "bson",
+		DefaultName:       lowercaseFieldName,
+		EmptyNameFallback: true,
+		InlineOption:      "inline",
+	}
+	// MsgpackTags follows `github.com/vmihailenco/msgpack` rules.
+	MsgpackTags = TagDialect{
+		Tag:               "msgpack",
+		DefaultName:       goFieldName,
+		EmptyNameFallback: true,
+		InlineOption:      "inline",
+		InlineEmbedded:    true,
+	}
+	// FormTags follows `github.com/go-playground/form` rules (used for query strings and form posts).
+	FormTags = TagDialect{
+		Tag:               "form",
+		DefaultName:       goFieldName,
+		EmptyNameFallback: true,
+		InlineEmbedded:    true,
+	}
+	// ProtobufTags follows protojson rules for structs generated by protoc-gen-go (lowerCamelCase names from the
+	// `protobuf` tag, and empty fields are omitted).
+	ProtobufTags = TagDialect{
+		Tag:         "protobuf",
+		DefaultName: goFieldName,
+		NameOptions: []string{"json", "name"},
+		OmitEmpty:   true,
+	}
+	// QueryTags is used for query parameter structs of API endpoints.
+	QueryTags = TagDialect{
+		Tag:               "query",
+		DefaultName:       goFieldName,
+		EmptyNameFallback: true,
+		InlineEmbedded:    true,
+	}
+)
+
+type tagInfo struct {
+	name      string
+	tagged    bool
+	ignored   bool
+	omitEmpty bool
+	inline    bool
+}
+
+func (d TagDialect) parse(field reflect.StructField) tagInfo {
+	var info tagInfo
+	tag, found := field.Tag.Lookup(d.Tag)
+	if !found || tag == "" {
+		return info
+	}
+	info.tagged = true
+	info.omitEmpty = d.OmitEmpty
+	parts := strings.Split(tag, ",")
+	if len(d.NameOptions) > 0 {
+		info.name = tagOption(parts, d.NameOptions)
+		return info
+	}
+	info.name = parts[0]
+	if info.name == "-" && len(parts) == 1 {
+		info.ignored = true
+		return info
+	}
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "omitempty":
+			info.omitEmpty = true
+		case d.InlineOption != "" && opt == d.InlineOption:
+			info.inline = true
+		}
+	}
+	return info
+}
+
+// tagOption returns the value of the first `name=value` option found.
+func tagOption(parts []string, names []string) string {
+	for _, name := range names {
+		for _, part := range parts {
+			if strings.HasPrefix(part, name+"=") {
+				return strings.TrimPrefix(part, name+"=")
+			}
+		}
+	}
+	return ""
+}
+
+// isInlined returns true if the fields of the (struct) field must be flattened into the parent struct.
+func (d TagDialect) isInlined(field reflect.StructField) bool {
+	info := d.parse(field)
+	if info.ignored {
+		return false
+	}
+	if info.inline {
+		return true
+	}
+	return field.Anonymous && d.InlineEmbedded && info.name == ""
+}
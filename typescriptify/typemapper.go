@@ -0,0 +1,76 @@
+package typescriptify
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TypeMapping is the typescript representation of a Golang type, returned by a TypeMapper.
+type TypeMapping struct {
+	TSType      string   // Typescript type, i.e. `Decimal`
+	TSTransform string   // Constructor expression, `__VALUE__` is the JSON value, i.e. `new Decimal(__VALUE__)`
+	TSSerialize string   // Expression used in `toJSON()`, `__VALUE__` is the typescript value, i.e. `__VALUE__.toString()`
+	Imports     []string // Imports needed by the type, i.e. `import { Decimal } from 'decimal.js';`
+}
+
+// FieldContext is the struct field being converted.
+type FieldContext struct {
+	Struct reflect.Type
+	Field  reflect.StructField
+	// Path is the Golang field name, or dotted path for fields of embedded structs.
+	Path string
+}
+
+// TypeMapper maps Golang types to typescript. Mappers are called (in the order they were registered) before the
+// built-in conversion, the first mapping found is used.
+//
+// Fields with a `ts_type` or `ts_transform` tag (or options set with `ManageType` and `WithFieldOpts*`) aren't mapped.
+type TypeMapper interface {
+	// MapType returns the mapping of typ (the field type, without pointer) and true, or false if the type isn't handled.
+	MapType(typ reflect.Type, field FieldContext) (TypeMapping, bool)
+}
+
+// TypeMapperFunc is a function implementing TypeMapper.
+type TypeMapperFunc func(typ reflect.Type, field FieldContext) (TypeMapping, bool)
+
+func (f TypeMapperFunc) MapType(typ reflect.Type, field FieldContext) (TypeMapping, bool) {
+	return f(typ, field)
+}
+
+// TypeMapperFor maps the Golang type of value (and pointers to it).
+func TypeMapperFor(value interface{}, mapping TypeMapping) TypeMapper {
+	typ := reflect.TypeOf(value)
+	if ty, is := value.(reflect.Type); is {
+		typ = ty
+	}
+	return TypeMapperFunc(func(t reflect.Type, _ FieldContext) (TypeMapping, bool) {
+		return mapping, t == typ
+	})
+}
+
+// WithTypeMapper adds a type mapper to the chain of mappers.
+func (t *TypeScriptify) WithTypeMapper(m TypeMapper) *TypeScriptify {
+	t.typeMappers = append(t.typeMappers, m)
+	return t
+}
+
+// mapType returns the options from the first type mapper handling the field type.
+func (t *TypeScriptify) mapType(field FieldContext) (TypeOptions, bool) {
+	mappers := t.typeMappers
+	if t.Protobuf {
+		mappers = append(append([]TypeMapper{}, mappers...), ProtobufTypeMapper)
+	}
+	for _, mapper := range mappers {
+		mapping, found := mapper.MapType(field.Field.Type, field)
+		if !found {
+			continue
+		}
+		return TypeOptions{
+			TSType:      mapping.TSType,
+			TSTransform: mapping.TSTransform,
+			TSSerialize: mapping.TSSerialize,
+			Import:      strings.Join(mapping.Imports, "\n"),
+		}, true
+	}
+	return TypeOptions{}, false
+}
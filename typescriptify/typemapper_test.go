@@ -0,0 +1,130 @@
+package typescriptify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeMapper(t *testing.T) {
+	t.Parallel()
+
+	type Event struct {
+		Name      string     `json:"name"`
+		StartedAt time.Time  `json:"started_at"`
+		EndedAt   *time.Time `json:"ended_at,omitempty"`
+		Timeout   int64      `json:"timeout_ms"`
+	}
+
+	converter := New().WithIndent("\t").
+		WithTypeMapper(TypeMapperFor(time.Time{}, TypeMapping{
+			TSType:      "Date",
+			TSTransform: "new Date(__VALUE__)",
+			TSSerialize: "__VALUE__.toISOString()",
+		})).
+		WithTypeMapper(TypeMapperFunc(func(typ reflect.Type, field FieldContext) (TypeMapping, bool) {
+			return TypeMapping{TSType: "bigint", TSTransform: "BigInt(__VALUE__)", TSSerialize: "Number(__VALUE__)"},
+				typ.Kind() == reflect.Int64 && strings.HasSuffix(field.Field.Tag.Get("json"), "_ms")
+		})).
+		Add(Event{})
+
+	desiredResult := `export class Event {
+	name: string;
+	started_at: Date;
+	ended_at?: Date;
+	timeout_ms: bigint;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.name = source["name"];
+		this.started_at = new Date(source["started_at"]);
+		this.ended_at = source["ended_at"] == null ? source["ended_at"] : new Date(source["ended_at"]);
+		this.timeout_ms = BigInt(source["timeout_ms"]);
+	}
+
+	toJSON(): any {
+		return {
+			...this,
+			started_at: this.started_at.toISOString(),
+			ended_at: this.ended_at == null ? this.ended_at : this.ended_at.toISOString(),
+			timeout_ms: Number(this.timeout_ms),
+		};
+	}
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Event({"started_at": "2020-10-09T08:09:00.000Z", "ended_at": "2020-10-09T08:09:00.000Z", "timeout_ms": 5}).timeout_ms === BigInt(5)`,
+		`JSON.parse(JSON.stringify(new Event({"started_at": "2020-10-09T08:09:00.000Z", "ended_at": "2020-10-09T08:09:00.000Z", "timeout_ms": 5}))).timeout_ms === 5`,
+		`new Event({"started_at": "2020-10-09T08:09:00.000Z", "timeout_ms": 5}).ended_at === undefined`,
+		`!("ended_at" in JSON.parse(JSON.stringify(new Event({"started_at": "2020-10-09T08:09:00.000Z", "timeout_ms": 5}))))`,
+	})
+}
+
+func TestTypeMapperSerializeJSONNames(t *testing.T) {
+	t.Parallel()
+
+	type Event struct {
+		StartedAt time.Time `json:"started_at" ts_name:"startedAt"`
+	}
+
+	converter := New().WithIndent("\t").
+		WithTypeMapper(TypeMapperFor(time.Time{}, TypeMapping{
+			TSType:      "Date",
+			TSTransform: "new Date(__VALUE__)",
+			TSSerialize: "__VALUE__.toISOString()",
+		})).
+		Add(Event{})
+
+	desiredResult := `export class Event {
+	startedAt: Date;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.startedAt = new Date(source["started_at"]);
+	}
+
+	toJSON(): any {
+		return {
+			...this,
+			startedAt: undefined,
+			started_at: this.startedAt.toISOString(),
+		};
+	}
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`JSON.stringify(new Event({"started_at": "2020-10-09T08:09:00.000Z"})) === '{"started_at":"2020-10-09T08:09:00.000Z"}'`,
+	})
+}
+
+func TestTypeMapperImports(t *testing.T) {
+	t.Parallel()
+
+	type Price struct {
+		Amount float64 `json:"amount"`
+		Tax    float64 `json:"tax" ts_type:"number"`
+	}
+
+	decimalImport := "import { Decimal } from 'decimal.js';"
+	decimal := TypeMapperFor(reflect.TypeOf(float64(0)), TypeMapping{
+		TSType:      "Decimal",
+		TSTransform: "new Decimal(__VALUE__)",
+		Imports:     []string{decimalImport},
+	})
+
+	converter := New().WithTypeMapper(decimal).Add(Price{})
+	converter.AddImport(decimalImport)
+	ts, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(ts, decimalImport))
+	assert.True(t, strings.HasPrefix(ts, decimalImport+"\n"))
+	assert.Contains(t, ts, "amount: Decimal;")
+	// Tags are used before mappers:
+	assert.Contains(t, ts, "tax: number;")
+
+	// No imports if the type isn't used:
+	ts, err = New().WithTypeMapper(decimal).Add(Dummy{}).Convert(nil)
+	assert.Nil(t, err)
+	assert.NotContains(t, ts, "import")
+}
@@ -1,11 +1,12 @@
 package typescriptify
 
 import (
+	"bytes"
 	"fmt"
-	"io"
 	"os"
-	"path"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,7 +17,10 @@ const (
 	tsDocTag            = "ts_doc"
 	tsTransformTag      = "ts_transform"
 	tsType              = "ts_type"
-	jsonTag             = "json"
+	tsReadonlyTag       = "ts_readonly"
+	tsNameTag           = "ts_name"
+	tsOptionalTag       = "ts_optional"
+	tsTag               = "ts"
 	tsConvertValuesFunc = `convertValues(a: any, classs: any, asMap: boolean = false): any {
 	if (!a) {
 		return a;
@@ -36,17 +40,56 @@ const (
 }`
 )
 
+// Optionality overrides the default optionality of a field (pointers and `omitempty` fields are optional).
+type Optionality int
+
+const (
+	DefaultOptionality Optionality = iota
+	Optional
+	Required
+)
+
 // TypeOptions overrides options set by `ts_*` tags.
 type TypeOptions struct {
 	TSType      string
 	TSDoc       string
 	TSTransform string
+	TSReadonly  bool
+	TSName      string      // Typescript property name (the constructor still reads the JSON field)
+	TSOptional  Optionality // Force the typescript field to be optional or required
+	TSExclude   bool        // Exclude the field from typescript, but not from JSON
+	TSFake      string      // Value (or one of FakeHints) for the generated fake functions
+	TSSerialize string      // Expression (with `__VALUE__`) converting the typescript value in the generated `toJSON()`
+	Import      string      // Import needed by TSType, i.e. `import Decimal from 'decimal.js'` (added only if used)
 }
 
 // StructType stores settings for transforming one Golang struct.
 type StructType struct {
 	Type         reflect.Type
 	FieldOptions map[reflect.Type]TypeOptions
+	// FieldOptionsByName are keyed by Golang field name, or dotted path for fields of embedded structs.
+	FieldOptionsByName map[string]TypeOptions
+	// TagDialect overrides the converter tag dialect for this struct (and structs referenced by it).
+	TagDialect *TagDialect
+	// TSName overrides the typescript name (without prefix and suffix) of the struct.
+	TSName string
+	// Interface converts this struct into an interface, also when the converter creates classes.
+	Interface bool
+	// CustomCode is added to the class body, i.e. methods and getters (not to interfaces and declarations).
+	CustomCode []string
+	// CustomJSCode is added to the class body instead of CustomCode in JavaScriptOutput.
+	CustomJSCode []string
+}
+
+// TSCustomCoder can be implemented by Golang structs, the code is added to the body of the typescript class, i.e.
+// `get fullName(): string { return this.first + " " + this.last; }`. Interfaces and declarations don't get the code.
+type TSCustomCoder interface {
+	TSCustomCode() string
+}
+
+// JSCustomCoder is TSCustomCoder for JavaScriptOutput (typescript code can't be used in javascript classes).
+type JSCustomCoder interface {
+	JSCustomCode() string
 }
 
 func NewStruct(i interface{}) *StructType {
@@ -69,13 +112,59 @@ func (st *StructType) WithFieldOpts(i interface{}, opts TypeOptions) *StructType
 	return st
 }
 
+// WithFieldOptsByName sets options for one field, name is the Golang field name (i.e. `CreatedAt`) or a dotted path
+// for fields of embedded structs (i.e. `ObjectMeta.CreationTimestamp`).
+func (st *StructType) WithFieldOptsByName(name string, opts TypeOptions) *StructType {
+	if st.FieldOptionsByName == nil {
+		st.FieldOptionsByName = map[string]TypeOptions{}
+	}
+	st.FieldOptionsByName[name] = opts
+	return st
+}
+
+func (st *StructType) WithTagDialect(d TagDialect) *StructType {
+	st.TagDialect = &d
+	return st
+}
+
+func (st *StructType) WithTSName(name string) *StructType {
+	st.TSName = name
+	return st
+}
+
+func (st *StructType) WithInterface(b bool) *StructType {
+	st.Interface = b
+	return st
+}
+
+// WithCustomCode adds code (i.e. methods, getters or static members) to the class body.
+func (st *StructType) WithCustomCode(code string) *StructType {
+	st.CustomCode = append(st.CustomCode, code)
+	return st
+}
+
+// WithCustomJSCode adds code to the class body in JavaScriptOutput, see WithCustomCode.
+func (st *StructType) WithCustomJSCode(code string) *StructType {
+	st.CustomJSCode = append(st.CustomJSCode, code)
+	return st
+}
+
+// WithMethods adds methods to the class body, same as WithCustomCode for every method.
+func (st *StructType) WithMethods(methods ...string) *StructType {
+	for _, method := range methods {
+		st.WithCustomCode(method)
+	}
+	return st
+}
+
 type EnumType struct {
 	Type reflect.Type
 }
 
 type enumElement struct {
-	value interface{}
-	name  string
+	value   interface{}
+	name    string
+	goValue interface{} // Golang value, if different from the typescript value (i.e. protobuf enums)
 }
 
 type TypeScriptify struct {
@@ -84,30 +173,68 @@ type TypeScriptify struct {
 	Indent            string
 	CreateFromMethod  bool
 	CreateConstructor bool
-	BackupDir         string // If empty no backup
+	BackupDir         string        // If empty no backup
+	BackupKeep        int           // Number of backups kept (per file), 0 for all
+	BackupMaxAge      time.Duration // Older backups are removed, 0 for no limit
 	DontExport        bool
 	CreateInterface   bool
 	CustomJsonTag     string
-	customImports     []string
-	customCodeBefore  []string
-	customCodeAfter   []string
-	silent            bool
+	TagDialect        *TagDialect // If empty, json (or CustomJsonTag) is used
+	Readonly          bool        // Readonly properties, arrays and maps (and frozen objects if constructors are created)
+	FieldNamer        FieldNamer  // Naming strategy for fields without tags, if nil the Golang field name is used
+	TSPropertyNamer   FieldNamer  // Naming strategy for typescript properties, if nil the JSON field name is used
+	// InlineAnonymousStructs converts anonymous struct fields into object literal types, instead of named classes.
+	InlineAnonymousStructs bool
+	// OrphanedCustomCode handles custom code blocks of types which are no longer generated (a warning by default).
+	OrphanedCustomCode OrphanedCustomCode
+	// Output is the kind of generated code (typescript by default, see WithOutput).
+	Output Output
+	// ClassValidator adds class-validator and class-transformer decorators to class properties (see WithClassValidator).
+	ClassValidator bool
+	// Protobuf converts structs generated by protoc-gen-go as they are serialized with protojson (see WithProtobuf).
+	Protobuf bool
+	// CreateFakes adds a `fakePerson(overrides?: Partial<Person>)` function with deterministic values for every model.
+	CreateFakes bool
+	// AnonymousStructNamer names classes for anonymous struct fields, by default `PersonMeta` for `Person.Meta`.
+	AnonymousStructNamer func(parentName, fieldName string) string
+	customImports        []string
+	customCodeBefore     []string
+	customCodeAfter      []string
+	silent               bool
 
 	structTypes []StructType
 	enumTypes   []EnumType
 	enums       map[reflect.Type][]enumElement
+	unionTypes  []reflect.Type
+	unionErrors []error // invalid AddUnion calls, returned by Convert
+	unions      map[reflect.Type]unionType
+	endpoints   []Endpoint
+	constants   []constant
+	fixtures    []fixture
 	kinds       map[reflect.Kind]string
 
 	fieldTypeOptions map[reflect.Type]TypeOptions
+	typeMappers      []TypeMapper
 
 	// throwaway, used when converting
 	alreadyConverted map[reflect.Type]bool
+	tagDialects      []TagDialect
+	anonymousStructs map[anonymousStructKey]string // generated names
+	// anonymousBindings are the anonymous struct fields currently converted (identical anonymous structs have the same
+	// type, but are converted into a class per field):
+	anonymousBindings  map[reflect.Type]anonymousStructKey
+	anonymousConverted map[anonymousStructKey]bool
+	convertedNames     map[string]string // typescript names of converted structs, with the Golang type description
+	usedFieldOptions   map[fieldOptionsKey]bool
+	usedCustomCode     map[string]bool
+	usedImports        map[string]bool
 }
 
 func New() *TypeScriptify {
 	result := new(TypeScriptify)
 	result.Indent = "\t"
-	result.BackupDir = "."
+	result.BackupDir = DefaultBackupDir
+	result.BackupKeep = 10
 
 	kinds := make(map[reflect.Kind]string)
 
@@ -138,8 +265,14 @@ func New() *TypeScriptify {
 	return result
 }
 
-func deepFields(typeOf reflect.Type) []reflect.StructField {
-	fields := make([]reflect.StructField, 0)
+// structField is a (possibly promoted) struct field with its dotted Golang path, i.e. `Embedded.Name`.
+type structField struct {
+	reflect.StructField
+	path string
+}
+
+func (t *TypeScriptify) deepFields(typeOf reflect.Type, dialect TagDialect, pathPrefix string) []structField {
+	fields := make([]structField, 0)
 
 	if typeOf.Kind() == reflect.Ptr {
 		typeOf = typeOf.Elem()
@@ -153,14 +286,23 @@ func deepFields(typeOf reflect.Type) []reflect.StructField {
 		f := typeOf.Field(i)
 
 		kind := f.Type.Kind()
-		if f.Anonymous && kind == reflect.Struct {
+		inline := dialect.isInlined(f)
+		path := pathPrefix + f.Name
+		if t.Protobuf && strings.HasPrefix(f.Name, "XXX_") {
+			continue
+		} else if t.Protobuf && kind == reflect.Interface && f.Tag.Get(protobufOneofTag) != "" {
+			// Protojson flattens oneof fields into the message:
+			for _, member := range t.protobufOneofMembers(typeOf, f) {
+				fields = append(fields, t.deepFields(member, dialect, path+".")...)
+			}
+		} else if inline && kind == reflect.Struct {
 			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type)...)
-		} else if f.Anonymous && kind == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
+			fields = append(fields, t.deepFields(f.Type, dialect, path+".")...)
+		} else if inline && kind == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
 			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type.Elem())...)
+			fields = append(fields, t.deepFields(f.Type.Elem(), dialect, path+".")...)
 		} else {
-			fields = append(fields, f)
+			fields = append(fields, structField{StructField: f, path: path})
 		}
 	}
 
@@ -223,6 +365,13 @@ func (t *TypeScriptify) WithBackupDir(b string) *TypeScriptify {
 	return t
 }
 
+// WithBackupRetention sets how many backups are kept (0 for all), and the max age of backups (0 for no limit).
+func (t *TypeScriptify) WithBackupRetention(keep int, maxAge time.Duration) *TypeScriptify {
+	t.BackupKeep = keep
+	t.BackupMaxAge = maxAge
+	return t
+}
+
 func (t *TypeScriptify) WithPrefix(p string) *TypeScriptify {
 	t.Prefix = p
 	return t
@@ -238,6 +387,43 @@ func (t *TypeScriptify) WithCustomJsonTag(tag string) *TypeScriptify {
 	return t
 }
 
+func (t *TypeScriptify) WithReadonly(b bool) *TypeScriptify {
+	t.Readonly = b
+	return t
+}
+
+func (t *TypeScriptify) WithFakes(b bool) *TypeScriptify {
+	t.CreateFakes = b
+	return t
+}
+
+func (t *TypeScriptify) WithInlineAnonymousStructs(b bool) *TypeScriptify {
+	t.InlineAnonymousStructs = b
+	return t
+}
+
+// WithFieldNamer sets the naming strategy (i.e. `CamelCase`) for fields without tags, this must match the naming
+// strategy of your JSON encoder.
+func (t *TypeScriptify) WithFieldNamer(n FieldNamer) *TypeScriptify {
+	t.FieldNamer = n
+	return t
+}
+
+// WithTSPropertyNamer renames typescript properties, the constructor maps JSON field names to the typescript names
+// (and `toJSON()` back to the JSON names).
+//
+// Renamed properties in interfaces (and inlined anonymous structs) are a conversion error, JSON isn't mapped there.
+func (t *TypeScriptify) WithTSPropertyNamer(n FieldNamer) *TypeScriptify {
+	t.TSPropertyNamer = n
+	return t
+}
+
+// WithTagDialect sets the struct tag (and its naming rules) used for field names, see `YAMLTags`, `BSONTags`, ...
+func (t *TypeScriptify) WithTagDialect(d TagDialect) *TypeScriptify {
+	t.TagDialect = &d
+	return t
+}
+
 func (t *TypeScriptify) Add(obj interface{}) *TypeScriptify {
 	switch ty := obj.(type) {
 	case StructType:
@@ -257,10 +443,10 @@ func (t *TypeScriptify) AddType(typeOf reflect.Type) *TypeScriptify {
 	return t
 }
 
-func (t *typeScriptClassBuilder) AddMapField(fieldName string, field reflect.StructField) {
+func (t *typeScriptClassBuilder) AddMapField(fieldName, jsonFieldName string, field reflect.StructField) {
 	keyType := field.Type.Key()
 	valueType := field.Type.Elem()
-	valueTypeName := valueType.Name()
+	valueTypeName := t.structName(valueType)
 	if name, ok := t.types[valueType.Kind()]; ok {
 		valueTypeName = name
 	}
@@ -268,10 +454,8 @@ func (t *typeScriptClassBuilder) AddMapField(fieldName string, field reflect.Str
 		valueTypeName = valueType.Elem().Name() + "[]"
 	}
 	if valueType.Kind() == reflect.Ptr {
-		valueTypeName = valueType.Elem().Name()
+		valueTypeName = t.structName(valueType.Elem())
 	}
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-
 	keyTypeStr := keyType.Name()
 	// Key should always be string, no need for this:
 	// _, isSimple := t.types[keyType.Kind()]
@@ -280,11 +464,11 @@ func (t *typeScriptClassBuilder) AddMapField(fieldName string, field reflect.Str
 	// }
 
 	if valueType.Kind() == reflect.Struct {
-		t.fields = append(t.fields, fmt.Sprintf("%s%s: {[key: %s]: %s};", t.indent, fieldName, keyTypeStr, t.prefix+valueTypeName))
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = this.convertValues(source[\"%s\"], %s, true);", t.indent, t.indent, strippedFieldName, strippedFieldName, t.prefix+valueTypeName+t.suffix))
+		t.addField(fieldName, t.mapType(keyTypeStr, t.prefix+valueTypeName))
+		t.addInitializerFieldLine(fieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s, true)", jsonFieldName, t.prefix+valueTypeName+t.suffix))
 	} else {
-		t.fields = append(t.fields, fmt.Sprintf("%s%s: {[key: %s]: %s};", t.indent, fieldName, keyTypeStr, valueTypeName))
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = source[\"%s\"];", t.indent, t.indent, strippedFieldName, strippedFieldName))
+		t.addField(fieldName, t.mapType(keyTypeStr, valueTypeName))
+		t.addInitializerFieldLine(fieldName, fmt.Sprintf("source[\"%s\"]", jsonFieldName))
 	}
 }
 
@@ -342,17 +526,26 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 	if t.CreateFromMethod {
 		fmt.Fprintln(os.Stderr, "FromMethod METHOD IS DEPRECATED AND WILL BE REMOVED!!!!!!")
 	}
+	if len(t.unionErrors) > 0 {
+		return "", t.unionErrors[0]
+	}
+	if len(t.endpoints) > 0 && t.Output != TypeScriptOutput {
+		return "", fmt.Errorf("endpoints can only be converted to typescript")
+	}
 
 	t.alreadyConverted = make(map[reflect.Type]bool)
+	t.anonymousStructs = make(map[anonymousStructKey]string)
+	t.anonymousBindings = make(map[reflect.Type]anonymousStructKey)
+	t.anonymousConverted = make(map[anonymousStructKey]bool)
+	t.convertedNames = make(map[string]string)
+	t.usedFieldOptions = make(map[fieldOptionsKey]bool)
+	t.usedCustomCode = make(map[string]bool)
+	t.usedImports = make(map[string]bool)
 	depth := 0
 
+	header := t.customCodeBlock(customCode, CustomCodeHeader, "")
+	// The imports are added (after the header) when all the types are converted:
 	result := ""
-	if len(t.customImports) > 0 {
-		// Put the custom imports, i.e.: `import Decimal from 'decimal.js'`
-		for _, cimport := range t.customImports {
-			result += cimport + "\n"
-		}
-	}
 
 	if len(t.customCodeBefore) > 0 {
 		result += "\n"
@@ -364,7 +557,15 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 
 	for _, enumTyp := range t.enumTypes {
 		elements := t.enums[enumTyp.Type]
-		typeScriptCode, err := t.convertEnum(depth, enumTyp.Type, elements)
+		typeScriptCode, err := t.convertEnum(depth, enumTyp.Type, elements, customCode)
+		if err != nil {
+			return "", err
+		}
+		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
+	}
+
+	for _, unionTyp := range t.unionTypes {
+		typeScriptCode, err := t.convertUnion(depth, t.unions[unionTyp], customCode)
 		if err != nil {
 			return "", err
 		}
@@ -372,13 +573,39 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 	}
 
 	for _, strctTyp := range t.structTypes {
+		if t.Protobuf && isProtobufOneofWrapper(strctTyp.Type) { // Fields are flattened into the message
+			continue
+		}
 		typeScriptCode, err := t.convertType(depth, strctTyp.Type, customCode)
 		if err != nil {
 			return "", err
 		}
+		if typeScriptCode == "" { // Already converted
+			continue
+		}
 		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
 	}
 
+	if len(t.constants) > 0 {
+		constants, err := t.convertConstants()
+		if err != nil {
+			return "", err
+		}
+		result += "\n\n" + constants
+	}
+
+	if len(t.fixtures) > 0 {
+		fixtures, err := t.convertFixtures()
+		if err != nil {
+			return "", err
+		}
+		result += "\n\n" + fixtures
+	}
+
+	if endpoints := t.convertEndpoints(); endpoints != "" {
+		result += "\n\n" + endpoints
+	}
+
 	if len(t.customCodeAfter) > 0 {
 		result += "\n"
 		for _, code := range t.customCodeAfter {
@@ -387,111 +614,106 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 		result += "\n"
 	}
 
-	return result, nil
-}
-
-func loadCustomCode(fileName string) (map[string]string, error) {
-	result := make(map[string]string)
-	f, err := os.Open(fileName)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return result, nil
-		}
-		return result, err
+	if err := t.unusedFieldOptions(); err != nil {
+		return "", err
 	}
-	defer f.Close()
 
-	bytes, err := io.ReadAll(f)
+	orphaned, err := t.orphanedCustomCode(customCode)
 	if err != nil {
-		return result, err
+		return "", err
 	}
-
-	var currentName string
-	var currentValue string
-	lines := strings.Split(string(bytes), "\n")
-	for _, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmedLine, "//[") && strings.HasSuffix(trimmedLine, ":]") {
-			currentName = strings.Replace(strings.Replace(trimmedLine, "//[", "", -1), ":]", "", -1)
-			currentValue = ""
-		} else if trimmedLine == "//[end]" {
-			result[currentName] = strings.TrimRight(currentValue, " \t\r\n")
-			currentName = ""
-			currentValue = ""
-		} else if len(currentName) > 0 {
-			currentValue += line + "\n"
-		}
+	if orphaned != "" {
+		result += "\n\n" + orphaned
 	}
 
-	return result, nil
+	// Put the custom imports, i.e.: `import Decimal from 'decimal.js'`
+	return header + t.convertImports() + result, nil
 }
 
-func (t TypeScriptify) backup(fileName string) error {
-	fileIn, err := os.Open(fileName)
+// ConvertToFile converts the models and writes them into fileName (see ConvertToFileIfChanged).
+func (t TypeScriptify) ConvertToFile(fileName string) error {
+	_, err := t.ConvertToFileIfChanged(fileName)
+	return err
+}
+
+// ConvertToFileIfChanged converts the models and writes them into fileName, but only if the content changed (so that
+// file watchers aren't triggered needlessly). The file is replaced atomically, and left untouched if the conversion
+// fails.
+func (t TypeScriptify) ConvertToFileIfChanged(fileName string) (changed bool, err error) {
+	customCode, err := loadCustomCode(fileName)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
-		// No neet to backup, just return:
-		return nil
+		return false, err
 	}
-	defer fileIn.Close()
 
-	bytes, err := io.ReadAll(fileIn)
+	converted, err := t.Convert(customCode)
 	if err != nil {
-		return err
+		return false, err
 	}
+	content := []byte("/* Do not change, this code is generated from Golang structs */\n\n" + converted)
 
-	_, backupFn := path.Split(fmt.Sprintf("%s-%s.backup", fileName, time.Now().Format("2006-01-02T15_04_05.99")))
-	if t.BackupDir != "" {
-		backupFn = path.Join(t.BackupDir, backupFn)
+	if t.Output == JavaScriptOutput {
+		declarations := t
+		declarations.Output = DeclarationOutput
+		if changed, err = declarations.ConvertToFileIfChanged(declarationFileName(fileName)); err != nil {
+			return false, err
+		}
 	}
 
-	return os.WriteFile(backupFn, bytes, os.FileMode(0700))
-}
+	existing, err := os.ReadFile(fileName)
+	if err == nil && bytes.Equal(existing, content) {
+		return changed, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
 
-func (t TypeScriptify) ConvertToFile(fileName string) error {
 	if len(t.BackupDir) > 0 {
-		err := t.backup(fileName)
-		if err != nil {
-			return err
+		if err := t.backup(fileName); err != nil {
+			return false, err
 		}
 	}
 
-	customCode, err := loadCustomCode(fileName)
-	if err != nil {
-		return err
+	if err := writeFileAtomic(fileName, content); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	f, err := os.Create(fileName)
-	if err != nil {
-		return err
+// writeFileAtomic writes into a temporary file which is then renamed, so that readers never see a partially written
+// file.
+func writeFileAtomic(fileName string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(fileName); err == nil {
+		mode = info.Mode().Perm()
 	}
-	defer f.Close()
 
-	converted, err := t.Convert(customCode)
+	dir, base := filepath.Split(fileName)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, "."+base+".*.tmp")
 	if err != nil {
 		return err
 	}
-
-	if _, err := f.WriteString("/* Do not change, this code is generated from Golang structs */\n\n"); err != nil {
+	defer os.Remove(f.Name()) // Fails after the rename
+	if _, err := f.Write(content); err != nil {
+		f.Close()
 		return err
 	}
-	if _, err := f.WriteString(converted); err != nil {
+	if err := f.Close(); err != nil {
 		return err
 	}
-	if err != nil {
+	if err := os.Chmod(f.Name(), mode); err != nil {
 		return err
 	}
-
-	return nil
+	return os.Rename(f.Name(), fileName)
 }
 
 type TSNamer interface {
 	TSName() string
 }
 
-func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []enumElement) (string, error) {
+func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []enumElement, customCode map[string]string) (string, error) {
 	t.logf(depth, "Converting enum %s", typeOf.String())
 	if _, found := t.alreadyConverted[typeOf]; found { // Already converted
 		return "", nil
@@ -499,11 +721,15 @@ func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []e
 	t.alreadyConverted[typeOf] = true
 
 	entityName := t.Prefix + typeOf.Name() + t.Suffix
-	result := "enum " + entityName + " {\n"
+	if t.Output == JavaScriptOutput {
+		return t.convertJSEnum(entityName, elements, customCode), nil
+	}
+	result := t.declare() + "enum " + entityName + " {\n"
 
 	for _, val := range elements {
 		result += fmt.Sprintf("%s%s = %#v,\n", t.Indent, val.name, val.value)
 	}
+	result += t.customCodeBlock(customCode, entityName, t.Indent)
 
 	result += "}"
 
@@ -514,15 +740,60 @@ func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []e
 	return result, nil
 }
 
-func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.StructField) TypeOptions {
+// fieldOptionsKey identifies a `FieldOptionsByName` entry of a struct.
+type fieldOptionsKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// unusedFieldOptions returns an error if `FieldOptionsByName` of a converted struct has names or paths which matched no
+// field (i.e. a typo, or a path through a struct which isn't inlined).
+func (t *TypeScriptify) unusedFieldOptions() error {
+	var unused []string
+	for _, strct := range t.structTypes {
+		if !t.alreadyConverted[strct.Type] {
+			continue
+		}
+		for name := range strct.FieldOptionsByName {
+			if !t.usedFieldOptions[fieldOptionsKey{strct.Type, name}] {
+				unused = append(unused, strct.Type.Name()+"."+name)
+			}
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	sort.Strings(unused)
+	return fmt.Errorf("field options for fields which don't exist (or aren't converted): %s", strings.Join(unused, ", "))
+}
+
+// getFieldOptions merges options from tags, type overrides and name overrides, path is the dotted Golang field path.
+func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.StructField, path string) TypeOptions {
 	// By default use options defined by tags:
 	opts := TypeOptions{
 		TSTransform: field.Tag.Get(tsTransformTag),
 		TSType:      field.Tag.Get(tsType),
 		TSDoc:       field.Tag.Get(tsDocTag),
+		TSReadonly:  field.Tag.Get(tsReadonlyTag) == "true",
+		TSName:      field.Tag.Get(tsNameTag),
+		TSExclude:   field.Tag.Get(tsTag) == "-",
+		TSFake:      field.Tag.Get(tsFakeTag),
+	}
+	switch field.Tag.Get(tsOptionalTag) {
+	case "true":
+		opts.TSOptional = Optional
+	case "false":
+		opts.TSOptional = Required
 	}
 
 	overrides := []TypeOptions{}
+	fieldStruct, fieldPath := structType, path
+
+	// Options for fields of anonymous structs are set on the (named) struct containing them:
+	anonymousKey, anonymous := t.anonymousBindings[structType]
+	if anonymous {
+		structType, path = anonymousKey.parent, anonymousKey.path+"."+path
+	}
 
 	// But there is maybe an struct-specific override:
 	for _, strct := range t.structTypes {
@@ -540,6 +811,21 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 		overrides = append(overrides, fldOpts)
 	}
 
+	// Name overrides are the most specific:
+	for _, strct := range t.structTypes {
+		if strct.Type != structType || strct.FieldOptionsByName == nil {
+			continue
+		}
+		if fldOpts, found := strct.FieldOptionsByName[field.Name]; found && !anonymous {
+			overrides = append(overrides, fldOpts)
+			t.usedFieldOptions[fieldOptionsKey{structType, field.Name}] = true
+		}
+		if fldOpts, found := strct.FieldOptionsByName[path]; found && path != field.Name {
+			overrides = append(overrides, fldOpts)
+			t.usedFieldOptions[fieldOptionsKey{structType, path}] = true
+		}
+	}
+
 	for _, o := range overrides {
 		if o.TSTransform != "" {
 			opts.TSTransform = o.TSTransform
@@ -547,107 +833,348 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 		if o.TSType != "" {
 			opts.TSType = o.TSType
 		}
+		if o.TSDoc != "" {
+			opts.TSDoc = o.TSDoc
+		}
+		if o.TSReadonly {
+			opts.TSReadonly = true
+		}
+		if o.TSName != "" {
+			opts.TSName = o.TSName
+		}
+		if o.TSOptional != DefaultOptionality {
+			opts.TSOptional = o.TSOptional
+		}
+		if o.TSExclude {
+			opts.TSExclude = true
+		}
+		if o.TSFake != "" {
+			opts.TSFake = o.TSFake
+		}
+		if o.TSSerialize != "" {
+			opts.TSSerialize = o.TSSerialize
+		}
+		if o.Import != "" {
+			opts.Import = o.Import
+		}
+	}
+
+	if opts.TSType == "" && opts.TSTransform == "" {
+		if mapped, found := t.mapType(FieldContext{Struct: fieldStruct, Field: field, Path: fieldPath}); found {
+			opts.TSType, opts.TSTransform = mapped.TSType, mapped.TSTransform
+			if opts.TSSerialize == "" {
+				opts.TSSerialize = mapped.TSSerialize
+			}
+			if opts.Import == "" {
+				opts.Import = mapped.Import
+			}
+		}
 	}
 
 	return opts
 }
 
-func (t *TypeScriptify) getJSONFieldName(field reflect.StructField, isPtr bool) string {
-	jsonFieldName := ""
-	tag := jsonTag
+// tagDialect returns the tag dialect for a struct: the one set for the struct, or the one of the struct referencing it, or
+// the converter default.
+func (t *TypeScriptify) tagDialect(typeOf reflect.Type) TagDialect {
+	for _, strct := range t.structTypes {
+		if strct.Type == typeOf && strct.TagDialect != nil {
+			return *strct.TagDialect
+		}
+	}
+	if len(t.tagDialects) > 0 {
+		return t.tagDialects[len(t.tagDialects)-1]
+	}
+	if t.TagDialect != nil {
+		return *t.TagDialect
+	}
+	if t.Protobuf {
+		return ProtobufTags
+	}
+	dialect := JSONTags
 	if t.CustomJsonTag != "" {
-		tag = t.CustomJsonTag
-	}
-	jsonTag := field.Tag.Get(tag)
-	if len(jsonTag) > 0 {
-		jsonTagParts := strings.Split(jsonTag, ",")
-		if len(jsonTagParts) > 0 {
-			jsonFieldName = strings.Trim(jsonTagParts[0], t.Indent)
-		}
-		hasOmitEmpty := false
-		ignored := false
-		for _, t := range jsonTagParts {
-			if t == "" {
-				break
-			}
-			if t == "omitempty" {
-				hasOmitEmpty = true
-				break
-			}
-			if t == "-" {
-				ignored = true
-				break
-			}
+		dialect.Tag = t.CustomJsonTag
+	}
+	return dialect
+}
+
+func (t *TypeScriptify) getJSONFieldName(field reflect.StructField, isPtr bool, dialect TagDialect) string {
+	info := dialect.parse(field)
+	if info.ignored {
+		return ""
+	}
+	jsonFieldName := info.name
+	if !info.tagged || (jsonFieldName == "" && dialect.EmptyNameFallback) {
+		if /*field.IsExported()*/ field.PkgPath != "" {
+			return ""
 		}
-		if !ignored && isPtr || hasOmitEmpty {
-			jsonFieldName = fmt.Sprintf("%s?", jsonFieldName)
+		if t.FieldNamer != nil {
+			jsonFieldName = t.FieldNamer(field.Name)
+		} else {
+			jsonFieldName = dialect.DefaultName(field)
 		}
-	} else if /*field.IsExported()*/ field.PkgPath == "" {
-		jsonFieldName = field.Name
+	}
+	if jsonFieldName == "" {
+		return ""
+	}
+	if info.tagged && (isPtr || info.omitEmpty) {
+		jsonFieldName = fmt.Sprintf("%s?", jsonFieldName)
 	}
 	return jsonFieldName
 }
 
+// getTSFieldName returns the typescript property name for a JSON field name (both optionally with `?`).
+func (t *TypeScriptify) getTSFieldName(jsonFieldName string) string {
+	if t.TSPropertyNamer == nil {
+		return jsonFieldName
+	}
+	if strings.HasSuffix(jsonFieldName, "?") {
+		return t.TSPropertyNamer(strings.TrimSuffix(jsonFieldName, "?")) + "?"
+	}
+	return t.TSPropertyNamer(jsonFieldName)
+}
+
 func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode map[string]string) (string, error) {
-	if _, found := t.alreadyConverted[typeOf]; found { // Already converted
+	if key, anonymous := t.anonymousBindings[typeOf]; anonymous {
+		if t.anonymousConverted[key] {
+			return "", nil
+		}
+		t.anonymousConverted[key] = true
+	} else if _, found := t.alreadyConverted[typeOf]; found { // Already converted
 		return "", nil
 	}
 	t.logf(depth, "Converting type %s", typeOf.String())
 
 	t.alreadyConverted[typeOf] = true
 
-	entityName := t.Prefix + typeOf.Name() + t.Suffix
+	entityName := t.Prefix + t.structName(typeOf) + t.Suffix
+	if err := t.checkConvertedName(entityName, typeOf); err != nil {
+		return "", err
+	}
+	builder, dependencies, err := t.convertFields(depth, typeOf, customCode)
+	if err != nil {
+		return "", err
+	}
+
+	if t.CreateFromMethod {
+		t.CreateConstructor = true
+	}
+
+	result := dependencies
+	if t.Output == JavaScriptOutput {
+		result += t.convertJSType(typeOf, entityName, builder, customCode)
+	} else {
+		result += t.convertTSType(typeOf, entityName, builder, customCode)
+	}
+	result += t.convertProtobufOneofs(typeOf)
+
+	if t.CreateFakes {
+		fake, err := t.convertFake(typeOf)
+		if err != nil {
+			return "", err
+		}
+		result += "\n" + fake
+	}
+
+	return result, nil
+}
+
+// convertTSType returns the typescript class or interface (or its declaration).
+func (t *TypeScriptify) convertTSType(typeOf reflect.Type, entityName string, builder *typeScriptClassBuilder, customCode map[string]string) string {
+	declaration := t.Output == DeclarationOutput
 	result := ""
-	if t.CreateInterface {
+	if t.isInterface(typeOf) {
 		result += fmt.Sprintf("interface %s {\n", entityName)
 	} else {
-		result += fmt.Sprintf("class %s {\n", entityName)
+		result += fmt.Sprintf("%sclass %s {\n", t.declare(), entityName)
 	}
 	if !t.DontExport {
 		result = "export " + result
 	}
-	builder := typeScriptClassBuilder{
-		types:  t.kinds,
-		indent: t.Indent,
-		prefix: t.Prefix,
-		suffix: t.Suffix,
+
+	result += strings.Join(builder.fields, "\n") + "\n"
+	if !t.isInterface(typeOf) {
+		constructorBody := strings.Join(builder.constructorBody, "\n")
+		needsConvertValue := strings.Contains(constructorBody, "this.convertValues")
+		if t.CreateFromMethod {
+			if declaration {
+				result += fmt.Sprintf("\n%sstatic createFrom(source?: any): %s;\n", t.Indent, entityName)
+			} else {
+				result += fmt.Sprintf("\n%sstatic createFrom(source: any = {}) {\n", t.Indent)
+				result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
+				result += fmt.Sprintf("%s}\n", t.Indent)
+			}
+		}
+		if t.CreateConstructor {
+			if declaration {
+				result += fmt.Sprintf("\n%sconstructor(source?: any);\n", t.Indent)
+			} else {
+				result += fmt.Sprintf("\n%sconstructor(source: any = {}) {\n", t.Indent)
+				result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
+				result += constructorBody + "\n"
+				if t.Readonly {
+					result += t.Indent + t.Indent + "Object.freeze(this);\n"
+				}
+				result += fmt.Sprintf("%s}\n", t.Indent)
+			}
+		}
+		if needsConvertValue && (t.CreateConstructor || t.CreateFromMethod) {
+			if declaration {
+				result += fmt.Sprintf("\n%sconvertValues(a: any, classs: any, asMap?: boolean): any;\n", t.Indent)
+			} else {
+				result += "\n" + indentLines(strings.ReplaceAll(tsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
+			}
+		}
+		if len(builder.serializers) > 0 {
+			if declaration {
+				result += fmt.Sprintf("\n%stoJSON(): any;\n", t.Indent)
+			} else {
+				result += fmt.Sprintf("\n%stoJSON(): any {\n", t.Indent)
+				result += t.Indent + t.Indent + "return {\n"
+				result += t.Indent + t.Indent + t.Indent + "...this,\n"
+				result += strings.Join(builder.serializers, "\n") + "\n"
+				result += t.Indent + t.Indent + "};\n"
+				result += fmt.Sprintf("%s}\n", t.Indent)
+			}
+		}
+
+		// Methods with bodies can't be declared:
+		if code := t.structCustomCode(typeOf); code != "" && !declaration {
+			result += "\n" + code + "\n"
+		}
+	}
+
+	result += t.customCodeBlock(customCode, entityName, t.Indent)
+
+	result += "}"
+	return result
+}
+
+// mapsJSONFields returns true if the JSON fields are copied into the typescript properties by a constructor (so
+// typescript names can differ from JSON names), false for interfaces and inlined anonymous structs (which are plain JSON).
+func (t *TypeScriptify) mapsJSONFields(typeOf reflect.Type) bool {
+	if t.InlineAnonymousStructs && typeOf.Name() == "" {
+		return false
+	}
+	return !t.isInterface(typeOf)
+}
+
+// convertFields converts struct fields, returns the builder with the fields and the code of the types referenced by
+// the struct (which must be placed before it).
+func (t *TypeScriptify) convertFields(depth int, typeOf reflect.Type, customCode map[string]string) (*typeScriptClassBuilder, string, error) {
+	dialect := t.tagDialect(typeOf)
+	t.tagDialects = append(t.tagDialects, dialect)
+	defer func() { t.tagDialects = t.tagDialects[:len(t.tagDialects)-1] }()
+
+	builder := &typeScriptClassBuilder{
+		types:      t.kinds,
+		indent:     t.Indent,
+		prefix:     t.Prefix,
+		suffix:     t.Suffix,
+		structName: t.structName,
 	}
 
-	fields := deepFields(typeOf)
-	for _, field := range fields {
+	dependencies := ""
+	discriminators := t.unionDiscriminators(typeOf)
+	discriminatorsFound := map[string]bool{}
+	fields := t.deepFields(typeOf, dialect, "")
+	for _, deepField := range fields {
+		field := deepField.StructField
 		isPtr := field.Type.Kind() == reflect.Ptr
 		if isPtr {
 			field.Type = field.Type.Elem()
 		}
-		jsonFieldName := t.getJSONFieldName(field, isPtr)
-		if len(jsonFieldName) == 0 || jsonFieldName == "-" {
+		jsonFieldName, fieldName, fldOpts := t.fieldNames(typeOf, deepField, dialect)
+		if fldOpts.TSExclude {
+			t.logf(depth, "- excluded field %s.%s", typeOf.Name(), field.Name)
+			continue
+		}
+		if jsonFieldName == "" {
 			continue
 		}
+		renamed := strings.TrimSuffix(fieldName, "?") != jsonFieldName
+		if renamed && !t.mapsJSONFields(typeOf) {
+			if fldOpts.TSName != "" {
+				return nil, "", fmt.Errorf("%s.%s: ts_name %q differs from the JSON name %q, interfaces and inlined structs can't rename JSON fields", typeOf.Name(), deepField.path, fldOpts.TSName, jsonFieldName)
+			}
+			return nil, "", fmt.Errorf("%s.%s: TSPropertyNamer renames %q to %q, interfaces and inlined structs can't rename JSON fields", typeOf.Name(), deepField.path, jsonFieldName, strings.TrimSuffix(fieldName, "?"))
+		}
+
+		defer t.bindAnonymousStruct(typeOf, deepField)()
 
 		var err error
-		fldOpts := t.getFieldOptions(typeOf, field)
+		literal, isDiscriminator := discriminators[jsonFieldName]
+		if isDiscriminator {
+			fldOpts.TSType, fldOpts.TSTransform = literal, literal
+			discriminatorsFound[jsonFieldName] = true
+		}
+		builder.readonly = t.Readonly || fldOpts.TSReadonly
 		if fldOpts.TSDoc != "" {
 			builder.addFieldDefinitionLine("/** " + fldOpts.TSDoc + " */")
+			builder.doc = fldOpts.TSDoc
+		}
+		if fldOpts.TSSerialize != "" {
+			builder.addSerializer(fieldName, jsonFieldName, fldOpts.TSSerialize)
+			if strings.HasSuffix(fieldName, "?") && fldOpts.TSTransform != "" {
+				// Missing values stay missing (and are serialized as such):
+				fldOpts.TSTransform = "__VALUE__ == null ? __VALUE__ : " + fldOpts.TSTransform
+			}
+		} else if renamed {
+			// Serialized with the JSON name (i.e. by `JSON.stringify()`):
+			builder.addSerializer(fieldName, jsonFieldName, "__VALUE__")
+		}
+		t.useImport(fldOpts.Import)
+		if typ := valueStructType(field.Type); typ != nil && t.isInterface(typ) && !t.isInterface(typeOf) && fldOpts.TSType == "" && fldOpts.TSTransform == "" {
+			// Interfaces can't be instantiated in the class constructor:
+			typeScriptChunk, err := t.convertType(depth+1, typ, customCode)
+			if err != nil {
+				return nil, "", err
+			}
+			if typeScriptChunk != "" {
+				dependencies = typeScriptChunk + "\n" + dependencies
+			}
+			fldOpts.TSType = builder.wrapType(field.Type, t.Prefix+t.structName(typ)+t.Suffix)
+		}
+		if anonymous := anonymousStructType(field.Type); anonymous != nil {
+			if t.InlineAnonymousStructs && fldOpts.TSType == "" && fldOpts.TSTransform == "" {
+				t.logf(depth, "- inline struct %s.%s", typeOf.Name(), field.Name)
+				literal, typeScriptChunk, err := t.convertInlineStruct(depth+1, anonymous, customCode)
+				if err != nil {
+					return nil, "", err
+				}
+				if typeScriptChunk != "" {
+					dependencies = typeScriptChunk + "\n" + dependencies
+				}
+				fldOpts.TSType = builder.wrapType(field.Type, literal)
+			}
+		}
+		if t.decorateClass(typeOf) {
+			for _, decorator := range t.classValidatorDecorators(field.Type, strings.HasSuffix(fieldName, "?"), fldOpts, isDiscriminator) {
+				builder.addFieldDefinitionLine(decorator)
+			}
 		}
 		if fldOpts.TSTransform != "" {
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
+			err = builder.AddSimpleField(fieldName, jsonFieldName, field, fldOpts)
 		} else if _, isEnum := t.enums[field.Type]; isEnum {
 			t.logf(depth, "- enum field %s.%s", typeOf.Name(), field.Name)
-			builder.AddEnumField(jsonFieldName, field)
+			builder.AddEnumField(fieldName, jsonFieldName, field)
 		} else if fldOpts.TSType != "" { // Struct:
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
+			err = builder.AddSimpleField(fieldName, jsonFieldName, field, fldOpts)
+		} else if union, isUnion := t.unionFieldType(field.Type); isUnion {
+			t.logf(depth, "- union field %s.%s", typeOf.Name(), field.Name)
+			builder.AddUnionField(fieldName, jsonFieldName, field, t.unionName(union), t.unionFactoryName(union))
 		} else if field.Type.Kind() == reflect.Struct { // Struct:
 			t.logf(depth, "- struct %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
 			typeScriptChunk, err := t.convertType(depth+1, field.Type, customCode)
 			if err != nil {
-				return "", err
+				return nil, "", err
 			}
 			if typeScriptChunk != "" {
-				result = typeScriptChunk + "\n" + result
+				dependencies = typeScriptChunk + "\n" + dependencies
 			}
-			builder.AddStructField(jsonFieldName, field)
+			builder.AddStructField(fieldName, jsonFieldName, field)
 		} else if field.Type.Kind() == reflect.Map {
 			t.logf(depth, "- map field %s.%s", typeOf.Name(), field.Name)
 			// Also convert map key types if needed
@@ -661,10 +1188,10 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 			if keyTypeToConvert != nil {
 				typeScriptChunk, err := t.convertType(depth+1, keyTypeToConvert, customCode)
 				if err != nil {
-					return "", err
+					return nil, "", err
 				}
 				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
+					dependencies = typeScriptChunk + "\n" + dependencies
 				}
 			}
 			// Also convert map value types if needed
@@ -678,14 +1205,14 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 			if valueTypeToConvert != nil {
 				typeScriptChunk, err := t.convertType(depth+1, valueTypeToConvert, customCode)
 				if err != nil {
-					return "", err
+					return nil, "", err
 				}
 				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
+					dependencies = typeScriptChunk + "\n" + dependencies
 				}
 			}
 
-			builder.AddMapField(jsonFieldName, field)
+			builder.AddMapField(fieldName, jsonFieldName, field)
 		} else if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array { // Slice:
 			if field.Type.Elem().Kind() == reflect.Ptr { //extract ptr type
 				field.Type = field.Type.Elem()
@@ -701,59 +1228,241 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 				t.logf(depth, "- struct slice %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
 				typeScriptChunk, err := t.convertType(depth+1, field.Type.Elem(), customCode)
 				if err != nil {
-					return "", err
+					return nil, "", err
 				}
 				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
+					dependencies = typeScriptChunk + "\n" + dependencies
 				}
-				builder.AddArrayOfStructsField(jsonFieldName, field, arrayDepth)
+				builder.AddArrayOfStructsField(fieldName, jsonFieldName, field, arrayDepth)
 			} else { // Slice of simple fields:
 				t.logf(depth, "- slice field %s.%s", typeOf.Name(), field.Name)
-				err = builder.AddSimpleArrayField(jsonFieldName, field, arrayDepth, fldOpts)
+				err = builder.AddSimpleArrayField(fieldName, jsonFieldName, field, arrayDepth, fldOpts)
 			}
 		} else { // Simple field:
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
+			err = builder.AddSimpleField(fieldName, jsonFieldName, field, fldOpts)
 		}
 		if err != nil {
-			return "", err
+			return nil, "", err
 		}
 	}
 
-	if t.CreateFromMethod {
-		t.CreateConstructor = true
+	// Union members without the discriminator field in the Golang struct:
+	var discriminatorNames []string
+	for jsonFieldName := range discriminators {
+		if !discriminatorsFound[jsonFieldName] {
+			discriminatorNames = append(discriminatorNames, jsonFieldName)
+		}
+	}
+	sort.Strings(discriminatorNames)
+	for n := len(discriminatorNames) - 1; n >= 0; n-- {
+		jsonFieldName, literal := discriminatorNames[n], discriminators[discriminatorNames[n]]
+		fields, constructorBody, properties := builder.fields, builder.constructorBody, builder.properties
+		builder.fields, builder.constructorBody, builder.properties = nil, nil, nil
+		builder.readonly = t.Readonly
+		field := reflect.StructField{Name: jsonFieldName, Type: reflect.TypeOf("")}
+		opts := TypeOptions{TSType: literal, TSTransform: literal}
+		if t.decorateClass(typeOf) {
+			for _, decorator := range t.classValidatorDecorators(field.Type, false, opts, true) {
+				builder.addFieldDefinitionLine(decorator)
+			}
+		}
+		if err := builder.AddSimpleField(t.getTSFieldName(jsonFieldName), jsonFieldName, field, opts); err != nil {
+			return nil, "", err
+		}
+		builder.fields, builder.constructorBody = append(builder.fields, fields...), append(builder.constructorBody, constructorBody...)
+		builder.properties = append(builder.properties, properties...)
 	}
 
-	result += strings.Join(builder.fields, "\n") + "\n"
-	if !t.CreateInterface {
-		constructorBody := strings.Join(builder.constructorBody, "\n")
-		needsConvertValue := strings.Contains(constructorBody, "this.convertValues")
-		if t.CreateFromMethod {
-			result += fmt.Sprintf("\n%sstatic createFrom(source: any = {}) {\n", t.Indent)
-			result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
-			result += fmt.Sprintf("%s}\n", t.Indent)
+	return builder, dependencies, nil
+}
+
+// fieldNames returns the JSON name and the typescript name (with `?` if optional) of a struct field, and its options.
+// The names are empty if the field isn't converted.
+func (t *TypeScriptify) fieldNames(typeOf reflect.Type, deepField structField, dialect TagDialect) (string, string, TypeOptions) {
+	field := deepField.StructField
+	isPtr := field.Type.Kind() == reflect.Ptr
+	if isPtr {
+		field.Type = field.Type.Elem()
+	}
+	jsonFieldName := t.getJSONFieldName(field, isPtr, dialect)
+	if len(jsonFieldName) == 0 || jsonFieldName == "-" {
+		return "", "", TypeOptions{}
+	}
+
+	fldOpts := t.getFieldOptions(typeOf, field, deepField.path)
+	if fldOpts.TSExclude {
+		return "", "", fldOpts
+	}
+	fieldName := t.getTSFieldName(jsonFieldName)
+	jsonFieldName = strings.TrimSuffix(jsonFieldName, "?")
+	optional := strings.HasSuffix(fieldName, "?")
+	if fldOpts.TSName != "" {
+		fieldName = fldOpts.TSName
+	}
+	fieldName = strings.TrimSuffix(fieldName, "?")
+	switch fldOpts.TSOptional {
+	case Optional:
+		optional = true
+	case Required:
+		optional = false
+	}
+	if optional {
+		fieldName += "?"
+	}
+	return jsonFieldName, fieldName, fldOpts
+}
+
+// convertInlineStruct converts an anonymous struct into a typescript object literal type.
+func (t *TypeScriptify) convertInlineStruct(depth int, typeOf reflect.Type, customCode map[string]string) (string, string, error) {
+	builder, dependencies, err := t.convertFields(depth, typeOf, customCode)
+	if err != nil {
+		return "", "", err
+	}
+	if len(builder.fields) == 0 {
+		return "{}", dependencies, nil
+	}
+	return "{\n" + indentLinesWith(strings.Join(builder.fields, "\n"), t.Indent) + "\n" + t.Indent + "}", dependencies, nil
+}
+
+// anonymousStructType returns the anonymous struct type of a field (also in pointers, slices and maps), or nil.
+func anonymousStructType(typ reflect.Type) reflect.Type {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			if typ.Name() == "" {
+				return typ
+			}
+			return nil
+		default:
+			return nil
 		}
-		if t.CreateConstructor {
-			result += fmt.Sprintf("\n%sconstructor(source: any = {}) {\n", t.Indent)
-			result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
-			result += constructorBody + "\n"
-			result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+}
+
+// anonymousStructKey identifies an anonymous struct field: the (named) struct containing it, and the dotted path of the
+// field (also through other anonymous structs).
+type anonymousStructKey struct {
+	parent reflect.Type
+	path   string
+}
+
+// bindAnonymousStruct registers the anonymous struct of a field (if any), and binds its type to the field until the
+// returned function is called. Identical anonymous structs of different fields get their own names and field options.
+func (t *TypeScriptify) bindAnonymousStruct(parent reflect.Type, field structField) func() {
+	typ := anonymousStructType(field.Type)
+	if typ == nil {
+		return func() {}
+	}
+	if t.anonymousStructs == nil {
+		t.anonymousStructs = map[anonymousStructKey]string{}
+		t.anonymousBindings = map[reflect.Type]anonymousStructKey{}
+		t.anonymousConverted = map[anonymousStructKey]bool{}
+	}
+	key := anonymousStructKey{parent: parent, path: field.path}
+	if parentKey, found := t.anonymousBindings[parent]; found {
+		key = anonymousStructKey{parent: parentKey.parent, path: parentKey.path + "." + field.path}
+	}
+	if _, found := t.anonymousStructs[key]; !found {
+		namer := t.AnonymousStructNamer
+		if namer == nil {
+			namer = func(parentName, fieldName string) string { return parentName + fieldName }
 		}
-		if needsConvertValue && (t.CreateConstructor || t.CreateFromMethod) {
-			result += "\n" + indentLines(strings.ReplaceAll(tsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
+		t.anonymousStructs[key] = namer(t.structName(parent), field.path[strings.LastIndex(field.path, ".")+1:])
+	}
+	previous, bound := t.anonymousBindings[typ]
+	t.anonymousBindings[typ] = key
+	return func() {
+		// The first field stays bound (for references from constants and fixtures):
+		if bound {
+			t.anonymousBindings[typ] = previous
 		}
 	}
+}
+
+// checkConvertedName returns an error if another struct was converted with the same typescript name (i.e. the
+// generated name of an anonymous struct is also the name of a struct).
+func (t *TypeScriptify) checkConvertedName(entityName string, typeOf reflect.Type) error {
+	description := typeOf.String()
+	if key, anonymous := t.anonymousBindings[typeOf]; anonymous {
+		description = "anonymous struct " + key.parent.String() + "." + key.path
+	}
+	if other, found := t.convertedNames[entityName]; found && other != description {
+		return fmt.Errorf("%s and %s are both converted to %s (use AnonymousStructNamer or WithTSName to rename one)", other, description, entityName)
+	}
+	t.convertedNames[entityName] = description
+	return nil
+}
 
-	if customCode != nil {
-		code := customCode[entityName]
-		if len(code) != 0 {
-			result += t.Indent + "//[" + entityName + ":]\n" + code + "\n\n" + t.Indent + "//[end]\n"
+// structName returns the struct name, or the generated name for anonymous structs.
+func (t *TypeScriptify) structName(typ reflect.Type) string {
+	for _, strct := range t.structTypes {
+		if strct.Type == typ && strct.TSName != "" {
+			return strct.TSName
 		}
 	}
+	if typ.Name() != "" {
+		return typ.Name()
+	}
+	return t.anonymousStructs[t.anonymousBindings[typ]]
+}
 
-	result += "}"
+// isInterface returns true if the struct is converted into an interface (and not a class).
+func (t *TypeScriptify) isInterface(typ reflect.Type) bool {
+	if t.CreateInterface {
+		return true
+	}
+	for _, strct := range t.structTypes {
+		if strct.Type == typ && strct.Interface {
+			return true
+		}
+	}
+	return false
+}
 
-	return result, nil
+// structCustomCode returns the (indented) class body code from StructType.CustomCode and TSCustomCoder (or
+// StructType.CustomJSCode and JSCustomCoder in JavaScriptOutput).
+func (t *TypeScriptify) structCustomCode(typ reflect.Type) string {
+	js := t.Output == JavaScriptOutput
+	var chunks []string
+	for _, strct := range t.structTypes {
+		if strct.Type == typ && js {
+			chunks = append(chunks, strct.CustomJSCode...)
+		} else if strct.Type == typ {
+			chunks = append(chunks, strct.CustomCode...)
+		}
+	}
+	for _, value := range []interface{}{reflect.Zero(typ).Interface(), reflect.New(typ).Interface()} {
+		if coder, is := value.(JSCustomCoder); is && js {
+			chunks = append(chunks, coder.JSCustomCode())
+			break
+		}
+		if coder, is := value.(TSCustomCoder); is && !js {
+			chunks = append(chunks, coder.TSCustomCode())
+			break
+		}
+	}
+
+	var lines []string
+	for _, chunk := range chunks {
+		chunk = strings.Trim(strings.ReplaceAll(chunk, "\t", t.Indent), "\r\n")
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		for _, line := range strings.Split(chunk, "\n") {
+			if strings.TrimSpace(line) == "" {
+				lines = append(lines, "")
+			} else {
+				lines = append(lines, t.Indent+strings.TrimRight(line, " \t\r"))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (t *TypeScriptify) AddImport(i string) {
@@ -780,22 +1489,62 @@ type typeScriptClassBuilder struct {
 	fields               []string
 	createFromMethodBody []string
 	constructorBody      []string
+	serializers          []string     // `toJSON()` properties
+	properties           []tsProperty // fields, for JavaScript output
+	doc                  string       // doc of the next field
 	prefix, suffix       string
+	readonly             bool // current field is readonly
+	structName           func(reflect.Type) string
 }
 
-func (t *typeScriptClassBuilder) AddSimpleArrayField(fieldName string, field reflect.StructField, arrayDepth int, opts TypeOptions) error {
+// wrapType returns the typescript type of a (pointer, slice or map) field type where the innermost struct is typ.
+func (t *typeScriptClassBuilder) wrapType(fieldType reflect.Type, typ string) string {
+	switch fieldType.Kind() {
+	case reflect.Ptr:
+		return t.wrapType(fieldType.Elem(), typ)
+	case reflect.Slice, reflect.Array:
+		return t.arrayType(t.wrapType(fieldType.Elem(), typ), 1)
+	case reflect.Map:
+		return t.mapType(fieldType.Key().Name(), t.wrapType(fieldType.Elem(), typ))
+	}
+	return typ
+}
+
+// arrayType returns `T[][]`, or `readonly (readonly T[])[]` for readonly fields.
+func (t *typeScriptClassBuilder) arrayType(typ string, arrayDepth int) string {
+	if !t.readonly {
+		return typ + strings.Repeat("[]", arrayDepth)
+	}
+	for i := 0; i < arrayDepth; i++ {
+		if i > 0 {
+			typ = "(" + typ + ")"
+		}
+		typ = "readonly " + typ + "[]"
+	}
+	return typ
+}
+
+// mapType returns `{[key: K]: V}`, or `Readonly<{[key: K]: V}>` for readonly fields.
+func (t *typeScriptClassBuilder) mapType(keyType, valueType string) string {
+	typ := fmt.Sprintf("{[key: %s]: %s}", keyType, valueType)
+	if t.readonly {
+		return "Readonly<" + typ + ">"
+	}
+	return typ
+}
+
+func (t *typeScriptClassBuilder) AddSimpleArrayField(fieldName, jsonFieldName string, field reflect.StructField, arrayDepth int, opts TypeOptions) error {
 	fieldType, kind := field.Type.Elem().Name(), field.Type.Elem().Kind()
 	typeScriptType := t.types[kind]
 
 	if len(fieldName) > 0 {
-		strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
 		if len(opts.TSType) > 0 {
 			t.addField(fieldName, opts.TSType)
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
+			t.addInitializerFieldLine(fieldName, fmt.Sprintf("source[\"%s\"]", jsonFieldName))
 			return nil
 		} else if len(typeScriptType) > 0 {
-			t.addField(fieldName, fmt.Sprint(typeScriptType, strings.Repeat("[]", arrayDepth)))
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
+			t.addField(fieldName, t.arrayType(typeScriptType, arrayDepth))
+			t.addInitializerFieldLine(fieldName, fmt.Sprintf("source[\"%s\"]", jsonFieldName))
 			return nil
 		}
 	}
@@ -803,7 +1552,7 @@ func (t *typeScriptClassBuilder) AddSimpleArrayField(fieldName string, field ref
 	return fmt.Errorf("cannot find type for %s (%s/%s)", kind.String(), fieldName, fieldType)
 }
 
-func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.StructField, opts TypeOptions) error {
+func (t *typeScriptClassBuilder) AddSimpleField(fieldName, jsonFieldName string, field reflect.StructField, opts TypeOptions) error {
 	fieldType, kind := field.Type.Name(), field.Type.Kind()
 
 	typeScriptType := t.types[kind]
@@ -812,14 +1561,13 @@ func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.
 	}
 
 	if len(typeScriptType) > 0 && len(fieldName) > 0 {
-		strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
 		t.addField(fieldName, typeScriptType)
 		if opts.TSTransform == "" {
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
+			t.addInitializerFieldLine(fieldName, fmt.Sprintf("source[\"%s\"]", jsonFieldName))
 		} else {
-			val := fmt.Sprintf(`source["%s"]`, strippedFieldName)
+			val := fmt.Sprintf(`source["%s"]`, jsonFieldName)
 			expression := strings.Replace(opts.TSTransform, "__VALUE__", val, -1)
-			t.addInitializerFieldLine(strippedFieldName, expression)
+			t.addInitializerFieldLine(fieldName, expression)
 		}
 		return nil
 	}
@@ -827,30 +1575,55 @@ func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.
 	return fmt.Errorf("cannot find type for %s (%s/%s)", kind.String(), fieldName, fieldType)
 }
 
-func (t *typeScriptClassBuilder) AddEnumField(fieldName string, field reflect.StructField) {
+func (t *typeScriptClassBuilder) AddEnumField(fieldName, jsonFieldName string, field reflect.StructField) {
 	fieldType := field.Type.Name()
 	t.addField(fieldName, t.prefix+fieldType+t.suffix)
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
+	t.addInitializerFieldLine(fieldName, fmt.Sprintf("source[\"%s\"]", jsonFieldName))
 }
 
-func (t *typeScriptClassBuilder) AddStructField(fieldName string, field reflect.StructField) {
-	fieldType := field.Type.Name()
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
+func (t *typeScriptClassBuilder) AddStructField(fieldName, jsonFieldName string, field reflect.StructField) {
+	fieldType := t.structName(field.Type)
 	t.addField(fieldName, t.prefix+fieldType+t.suffix)
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
+	t.addInitializerFieldLine(fieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", jsonFieldName, t.prefix+fieldType+t.suffix))
 }
 
-func (t *typeScriptClassBuilder) AddArrayOfStructsField(fieldName string, field reflect.StructField, arrayDepth int) {
-	fieldType := field.Type.Elem().Name()
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addField(fieldName, fmt.Sprint(t.prefix+fieldType+t.suffix, strings.Repeat("[]", arrayDepth)))
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
+func (t *typeScriptClassBuilder) AddUnionField(fieldName, jsonFieldName string, field reflect.StructField, unionName, factoryName string) {
+	t.addField(fieldName, t.wrapType(field.Type, unionName))
+	if field.Type.Kind() == reflect.Map {
+		t.addInitializerFieldLine(fieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s, true)", jsonFieldName, factoryName))
+	} else {
+		t.addInitializerFieldLine(fieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", jsonFieldName, factoryName))
+	}
 }
 
+func (t *typeScriptClassBuilder) AddArrayOfStructsField(fieldName, jsonFieldName string, field reflect.StructField, arrayDepth int) {
+	fieldType := t.structName(field.Type.Elem())
+	t.addField(fieldName, t.arrayType(t.prefix+fieldType+t.suffix, arrayDepth))
+	t.addInitializerFieldLine(fieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", jsonFieldName, t.prefix+fieldType+t.suffix))
+}
+
+// addInitializerFieldLine adds a constructor line, fld is the typescript field name (optionally with `?`).
 func (t *typeScriptClassBuilder) addInitializerFieldLine(fld, initializer string) {
-	t.createFromMethodBody = append(t.createFromMethodBody, fmt.Sprint(t.indent, t.indent, "result.", fld, " = ", initializer, ";"))
-	t.constructorBody = append(t.constructorBody, fmt.Sprint(t.indent, t.indent, "this.", fld, " = ", initializer, ";"))
+	access := tsPropertyAccess(strings.TrimSuffix(fld, "?"))
+	t.createFromMethodBody = append(t.createFromMethodBody, fmt.Sprint(t.indent, t.indent, "result", access, " = ", initializer, ";"))
+	t.constructorBody = append(t.constructorBody, fmt.Sprint(t.indent, t.indent, "this", access, " = ", initializer, ";"))
+}
+
+// addSerializer adds a `toJSON()` property, fld is the typescript field name (with `?` if optional, then null and
+// undefined aren't serialized). The property is written with the JSON name.
+func (t *typeScriptClassBuilder) addSerializer(fld, jsonFieldName, serialize string) {
+	name := strings.TrimSuffix(fld, "?")
+	value := "this" + tsPropertyAccess(name)
+	expression := strings.ReplaceAll(serialize, "__VALUE__", value)
+	if strings.HasSuffix(fld, "?") && expression != value {
+		expression = value + " == null ? " + value + " : " + expression
+	}
+	prefix := t.indent + t.indent + t.indent
+	if name != jsonFieldName {
+		// Not copied with the typescript name by `...this`:
+		t.serializers = append(t.serializers, fmt.Sprint(prefix, tsPropertyName(name), ": undefined,"))
+	}
+	t.serializers = append(t.serializers, fmt.Sprint(prefix, tsPropertyName(jsonFieldName), ": ", expression, ","))
 }
 
 func (t *typeScriptClassBuilder) addFieldDefinitionLine(line string) {
@@ -858,5 +1631,15 @@ func (t *typeScriptClassBuilder) addFieldDefinitionLine(line string) {
 }
 
 func (t *typeScriptClassBuilder) addField(fld, fldType string) {
-	t.fields = append(t.fields, fmt.Sprint(t.indent, fld, ": ", fldType, ";"))
+	optional := ""
+	if strings.HasSuffix(fld, "?") {
+		optional = "?"
+	}
+	readonly := ""
+	if t.readonly {
+		readonly = "readonly "
+	}
+	t.fields = append(t.fields, fmt.Sprint(t.indent, readonly, tsPropertyName(strings.TrimSuffix(fld, "?")), optional, ": ", fldType, ";"))
+	t.properties = append(t.properties, tsProperty{name: strings.TrimSuffix(fld, "?"), optional: optional != "", readonly: t.readonly, tsType: fldType, doc: t.doc})
+	t.doc = ""
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -1076,3 +1077,725 @@ func TestTypescriptifyCustomJsonTag(t *testing.T) {
 }`
 	testConverter(t, converter, false, desiredResult, nil)
 }
+
+func TestTagDialects(t *testing.T) {
+	t.Parallel()
+
+	type Audit struct {
+		CreatedBy string `yaml:"created_by" bson:"createdBy"`
+	}
+	type Document struct {
+		ID      string   `yaml:"id" bson:"_id,omitempty"`
+		Title   string   `yaml:"title,omitempty" bson:"title"`
+		Tags    []string `yaml:",flow" bson:"tags,minsize"`
+		Ignored string   `yaml:"-" bson:"-"`
+		Audit   Audit    `yaml:",inline" bson:",inline"`
+		Version int
+	}
+
+	converter := New().WithTagDialect(YAMLTags).WithConstructor(false).Add(Document{})
+	desiredResult := `export class Document {
+	id: string;
+	title?: string;
+	tags: string[];
+	created_by: string;
+	version: number;
+}`
+	testConverter(t, converter, false, desiredResult, nil)
+
+	converter = New().WithConstructor(false).Add(NewStruct(Document{}).WithTagDialect(BSONTags))
+	desiredResult = `export class Document {
+	_id?: string;
+	title: string;
+	tags: string[];
+	createdBy: string;
+	version: number;
+}`
+	testConverter(t, converter, false, desiredResult, nil)
+}
+
+func TestMsgpackAndFormTags(t *testing.T) {
+	t.Parallel()
+
+	type Paging struct {
+		Page int `msgpack:"page" form:"page"`
+	}
+	type Meta struct {
+		Source string `msgpack:"source"`
+	}
+	type Search struct {
+		Paging
+		Meta     Meta     `msgpack:",inline" form:"meta"`
+		Query    string   `msgpack:"q" form:"q"`
+		Labels   []string `msgpack:",omitempty" form:",omitempty"`
+		Secret   string   `msgpack:"-" form:"-"`
+		Internal string   `json:"internal"`
+	}
+
+	converter := New().WithTagDialect(MsgpackTags).WithConstructor(false).Add(Search{})
+	desiredResult := `export class Search {
+	page: number;
+	source: string;
+	q: string;
+	Labels?: string[];
+	Internal: string;
+}`
+	testConverter(t, converter, false, desiredResult, nil)
+
+	converter = New().WithConstructor(false).Add(NewStruct(Search{}).WithTagDialect(FormTags))
+	desiredResult = `export class Meta {
+	Source: string;
+}
+export class Search {
+	page: number;
+	meta: Meta;
+	q: string;
+	Labels?: string[];
+	Internal: string;
+}`
+	testConverter(t, converter, false, desiredResult, nil)
+}
+
+func TestTagDialectInherited(t *testing.T) {
+	t.Parallel()
+
+	type Embedded struct {
+		Note string `bson:"note"`
+	}
+	type Child struct {
+		Embedded
+		Value string `bson:"val"`
+	}
+	type Parent struct {
+		Child Child `bson:"child"`
+	}
+
+	converter := New().WithConstructor(false).Add(NewStruct(Parent{}).WithTagDialect(BSONTags))
+	desiredResult := `export class Embedded {
+	note: string;
+}
+export class Child {
+	embedded: Embedded;
+	val: string;
+}
+export class Parent {
+	child: Child;
+}`
+	testConverter(t, converter, false, desiredResult, nil)
+}
+
+func TestFieldNamers(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []struct {
+		name, camel, snake, kebab string
+	}{
+		{"FirstName", "firstName", "first_name", "first-name"},
+		{"ID", "id", "id", "id"},
+		{"UserID", "userID", "user_id", "user-id"},
+		{"HTTPServer", "httpServer", "http_server", "http-server"},
+		{"first_name", "firstName", "first_name", "first-name"},
+		{"Address2Line", "address2Line", "address2_line", "address2-line"},
+	} {
+		assert.Equal(t, data.camel, CamelCase(data.name), data.name)
+		assert.Equal(t, data.snake, SnakeCase(data.name), data.name)
+		assert.Equal(t, data.kebab, KebabCase(data.name), data.name)
+	}
+}
+
+func TestFieldNamerForUntaggedFields(t *testing.T) {
+	t.Parallel()
+
+	type Person struct {
+		FirstName string
+		LastName  string `json:"surname"`
+		Nickname  *string
+	}
+
+	converter := New().WithFieldNamer(CamelCase).WithConstructor(false).Add(Person{})
+	desiredResult := `export class Person {
+	firstName: string;
+	surname: string;
+	nickname: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+
+	converter = New().WithFieldNamer(KebabCase).WithConstructor(false).Add(Person{})
+	desiredResult = `export class Person {
+	"first-name": string;
+	surname: string;
+	nickname: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestTSPropertyNamer(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		StreetName string `json:"street_name"`
+	}
+	type Person struct {
+		FirstName string    `json:"first_name"`
+		Addresses []Address `json:"home_addresses,omitempty"`
+	}
+
+	converter := New().WithTSPropertyNamer(CamelCase).Add(Person{})
+	desiredResult := `export class Address {
+	streetName: string;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.streetName = source["street_name"];
+	}
+
+	toJSON(): any {
+		return {
+			...this,
+			streetName: undefined,
+			street_name: this.streetName,
+		};
+	}
+}
+export class Person {
+	firstName: string;
+	homeAddresses?: Address[];
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.firstName = source["first_name"];
+		this.homeAddresses = this.convertValues(source["home_addresses"], Address);
+	}
+
+	` + tsConvertValuesFunc + `
+
+	toJSON(): any {
+		return {
+			...this,
+			firstName: undefined,
+			first_name: this.firstName,
+			homeAddresses: undefined,
+			home_addresses: this.homeAddresses,
+		};
+	}
+}`
+	jsn := jsonizeOrPanic(Person{FirstName: "Jane", Addresses: []Address{{StreetName: "Ilica"}}})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Person(` + jsn + `).firstName === "Jane"`,
+		`new Person(` + jsn + `).homeAddresses?.[0]?.streetName === "Ilica"`,
+		`JSON.stringify(new Person(` + jsn + `)) === ` + "`" + jsn + "`",
+	})
+
+	_, err := New().WithInterface(true).WithTSPropertyNamer(CamelCase).Add(Person{}).Convert(nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `TSPropertyNamer renames "first_name" to "firstName"`)
+}
+
+func TestReadonly(t *testing.T) {
+	t.Parallel()
+
+	type Point struct {
+		X float64 `json:"x"`
+	}
+	type Shape struct {
+		Name   string            `json:"name"`
+		Points []Point           `json:"points"`
+		Grid   [][]int           `json:"grid"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+
+	converter := New().WithReadonly(true).Add(Shape{})
+	desiredResult := `export class Point {
+	readonly x: number;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.x = source["x"];
+		Object.freeze(this);
+	}
+}
+export class Shape {
+	readonly name: string;
+	readonly points: readonly Point[];
+	readonly grid: readonly (readonly number[])[];
+	readonly labels?: Readonly<{[key: string]: string}>;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.name = source["name"];
+		this.points = this.convertValues(source["points"], Point);
+		this.grid = source["grid"];
+		this.labels = source["labels"];
+		Object.freeze(this);
+	}
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := jsonizeOrPanic(Shape{Name: "triangle", Points: []Point{{X: 1}}})
+	testConverter(t, converter, true, desiredResult, []string{
+		`Object.isFrozen(new Shape(` + jsn + `))`,
+		`Object.isFrozen(new Shape(` + jsn + `).points[0])`,
+	})
+}
+
+func TestReadonlyTag(t *testing.T) {
+	t.Parallel()
+
+	type Account struct {
+		ID    string   `json:"id" ts_readonly:"true"`
+		Roles []string `json:"roles" ts_readonly:"true"`
+		Name  string   `json:"name"`
+	}
+
+	converter := New().WithInterface(true).Add(Account{})
+	desiredResult := `export interface Account {
+	readonly id: string;
+	readonly roles: readonly string[];
+	name: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestTSFieldOverrideTags(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		ID       string  `json:"user_id" ts_name:"id"`
+		Email    string  `json:"email,omitempty" ts_optional:"false"`
+		Phone    string  `json:"phone" ts_optional:"true"`
+		Password string  `json:"password" ts:"-"`
+		Manager  *string `json:"manager" ts_name:"managerId"`
+	}
+
+	converter := New().Add(User{})
+	desiredResult := `export class User {
+	id: string;
+	email: string;
+	phone?: string;
+	managerId?: string;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.id = source["user_id"];
+		this.email = source["email"];
+		this.phone = source["phone"];
+		this.managerId = source["manager"];
+	}
+
+	toJSON(): any {
+		return {
+			...this,
+			id: undefined,
+			user_id: this.id,
+			managerId: undefined,
+			manager: this.managerId,
+		};
+	}
+}`
+	jsn := jsonizeOrPanic(User{ID: "u1"})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new User(` + jsn + `).id === "u1"`,
+	})
+}
+
+func TestTSNameInInterfaces(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		ID    string `json:"user_id" ts_name:"id"`
+		Email string `json:"email" ts_name:"email"`
+	}
+	type Account struct {
+		Owner struct {
+			ID string `json:"user_id" ts_name:"id"`
+		} `json:"owner"`
+	}
+
+	for _, converter := range []*TypeScriptify{
+		New().WithInterface(true).Add(User{}),
+		New().Add(NewStruct(User{}).WithInterface(true)),
+		New().WithInlineAnonymousStructs(true).Add(Account{}),
+	} {
+		_, err := converter.Convert(nil)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `ts_name "id" differs from the JSON name "user_id"`)
+	}
+
+	type Contact struct {
+		Email string `json:"email" ts_name:"email"`
+	}
+	ts, err := New().WithInterface(true).Add(Contact{}).Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, "email: string;")
+}
+
+func TestTSFieldOverrideOptions(t *testing.T) {
+	t.Parallel()
+
+	type Secret string
+	type Session struct {
+		Token   Secret    `json:"token"`
+		Expires time.Time `json:"expires,omitempty"`
+	}
+
+	converter := New().
+		WithConstructor(false).
+		ManageType(Secret(""), TypeOptions{TSExclude: true}).
+		Add(NewStruct(Session{}).WithFieldOpts(time.Time{}, TypeOptions{TSType: "string", TSName: "expiresAt", TSOptional: Required}))
+	desiredResult := `export class Session {
+	expiresAt: string;
+
+	toJSON(): any {
+		return {
+			...this,
+			expiresAt: undefined,
+			expires: this.expiresAt,
+		};
+	}
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestFieldOptsByName(t *testing.T) {
+	t.Parallel()
+
+	type ObjectMeta struct {
+		Name              string    `json:"name"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	}
+	type Pod struct {
+		ObjectMeta
+		NodeName  string    `json:"nodeName"`
+		Phase     string    `json:"phase"`
+		StartTime time.Time `json:"startTime"`
+	}
+
+	converter := New().
+		WithConstructor(false).
+		Add(NewStruct(Pod{}).
+			WithFieldOpts(time.Time{}, TypeOptions{TSType: "string"}).
+			WithFieldOptsByName("Phase", TypeOptions{TSType: `"Pending" | "Running"`}).
+			WithFieldOptsByName("ObjectMeta.CreationTimestamp", TypeOptions{TSType: "Date", TSDoc: "never sent"}).
+			WithFieldOptsByName("NodeName", TypeOptions{TSOptional: Optional}))
+	desiredResult := `export class Pod {
+	name: string;
+	/** never sent */
+	creationTimestamp: Date;
+	nodeName?: string;
+	phase: "Pending" | "Running";
+	startTime: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestFieldOptsByNameNotFound(t *testing.T) {
+	t.Parallel()
+
+	type ObjectMeta struct {
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	}
+	type Pod struct {
+		ObjectMeta `json:"metadata"` // Not inlined
+		Phase      string            `json:"phase"`
+	}
+
+	_, err := New().
+		Add(NewStruct(Pod{}).
+			WithFieldOptsByName("ObjectMeta.CreationTimestamp", TypeOptions{TSType: "Date"}).
+			WithFieldOptsByName("Phase", TypeOptions{TSType: `"Pending" | "Running"`}).
+			WithFieldOptsByName("Status", TypeOptions{TSType: "string"})).
+		Convert(nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Pod.ObjectMeta.CreationTimestamp, Pod.Status")
+
+	// Options of the nested struct are set on it:
+	ts, err := New().WithConstructor(false).
+		Add(NewStruct(Pod{})).
+		Add(NewStruct(ObjectMeta{}).WithFieldOptsByName("CreationTimestamp", TypeOptions{TSType: "Date"})).
+		Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, ts, "creationTimestamp: Date;")
+}
+
+type PersonWithAnonymousStructs struct {
+	Name string `json:"name"`
+	Meta struct {
+		Created string `json:"created"`
+		Tags    []struct {
+			Key string `json:"key"`
+		} `json:"tags"`
+	} `json:"meta"`
+	Extra   *struct{ Note string } `json:"extra"`
+	ByLabel map[string]struct {
+		Value int `json:"value"`
+	} `json:"by_label"`
+}
+
+func TestAnonymousStructsAsClasses(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithConstructor(false).Add(PersonWithAnonymousStructs{})
+	desiredResult := `export class PersonWithAnonymousStructsByLabel {
+	value: number;
+}
+export class PersonWithAnonymousStructsExtra {
+	Note: string;
+}
+export class PersonWithAnonymousStructsMetaTags {
+	key: string;
+}
+export class PersonWithAnonymousStructsMeta {
+	created: string;
+	tags: PersonWithAnonymousStructsMetaTags[];
+}
+export class PersonWithAnonymousStructs {
+	name: string;
+	meta: PersonWithAnonymousStructsMeta;
+	extra?: PersonWithAnonymousStructsExtra;
+	by_label: {[key: string]: PersonWithAnonymousStructsByLabel};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+
+	converter = New().WithConstructor(false).
+		Add(NewStruct(PersonWithAnonymousStructs{}).WithFieldOptsByName("Meta.Created", TypeOptions{TSType: "Date"}))
+	converter.AnonymousStructNamer = func(parentName, fieldName string) string { return parentName + "_" + fieldName }
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, "export class PersonWithAnonymousStructs_Meta_Tags {")
+	assert.Contains(t, typeScriptCode, "created: Date;")
+}
+
+func TestIdenticalAnonymousStructs(t *testing.T) {
+	t.Parallel()
+
+	type Range struct {
+		From struct {
+			Value int `json:"value"`
+		} `json:"from"`
+		To struct {
+			Value int `json:"value"`
+		} `json:"to"`
+	}
+	type Period struct {
+		Start struct {
+			Value int `json:"value"`
+		} `json:"start"`
+	}
+
+	converter := New().WithIndent("\t").WithConstructor(false).WithFakes(true).
+		Add(NewStruct(Range{}).WithFieldOptsByName("To.Value", TypeOptions{TSType: "string"})).
+		Add(Period{})
+	desiredResult := `export class RangeFrom {
+	value: number;
+}
+export class RangeTo {
+	value: string;
+}
+export class Range {
+	from: RangeFrom;
+	to: RangeTo;
+}
+export class PeriodStart {
+	value: number;
+}
+export class Period {
+	start: PeriodStart;
+}`
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	for _, class := range strings.Split(desiredResult, "\nexport ") {
+		assert.Contains(t, typeScriptCode, strings.TrimPrefix(class, "export "))
+	}
+	assert.Contains(t, typeScriptCode, "from: fakeRangeFrom(),")
+	assert.Contains(t, typeScriptCode, "to: fakeRangeTo(),")
+	assert.Contains(t, typeScriptCode, "start: fakePeriodStart(),")
+}
+
+type CollidingMeta struct {
+	Created string `json:"created"`
+}
+
+type Colliding struct {
+	Meta struct {
+		Updated string `json:"updated"`
+	} `json:"meta"`
+	Other CollidingMeta `json:"other"`
+}
+
+func TestAnonymousStructNameCollision(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().Add(Colliding{}).Convert(nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "are both converted to CollidingMeta")
+
+	converter := New().Add(Colliding{})
+	converter.AnonymousStructNamer = func(parentName, fieldName string) string { return parentName + "_" + fieldName }
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, "export class Colliding_Meta {")
+	assert.Contains(t, typeScriptCode, "export class CollidingMeta {")
+}
+
+func TestAnonymousStructsInline(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithInterface(true).WithInlineAnonymousStructs(true).Add(PersonWithAnonymousStructs{})
+	desiredResult := `export interface PersonWithAnonymousStructs {
+	name: string;
+	meta: {
+		created: string;
+		tags: {
+			key: string;
+		}[];
+	};
+	extra?: {
+		Note: string;
+	};
+	by_label: {[key: string]: {
+		value: number;
+	}};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestStructTSNameAndInterface(t *testing.T) {
+	t.Parallel()
+
+	type Owner struct {
+		Name string `json:"name"`
+	}
+	type Pet struct {
+		Owner  Owner            `json:"owner"`
+		Owners []Owner          `json:"owners"`
+		ByName map[string]Owner `json:"by_name"`
+	}
+
+	converter := New().WithIndent("\t").
+		Add(NewStruct(Owner{}).WithTSName("PetOwner").WithInterface(true)).
+		Add(Pet{})
+
+	desiredResult := `export interface PetOwner {
+	name: string;
+}
+export class Pet {
+	owner: PetOwner;
+	owners: PetOwner[];
+	by_name: {[key: string]: PetOwner};
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.owner = source["owner"];
+		this.owners = source["owners"];
+		this.by_name = source["by_name"];
+	}
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Pet({"owner": {"name": "Alice"}}).owner.name === "Alice"`,
+	})
+}
+
+type FullNamePerson struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+func (FullNamePerson) TSCustomCode() string {
+	return `get fullName(): string {
+	return this.first + " " + this.last;
+}`
+}
+
+func TestStructCustomCode(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").
+		Add(NewStruct(FullNamePerson{}).
+			WithCustomCode("static readonly KIND = \"person\";").
+			WithMethods("greet(): string {\n\treturn \"Hello \" + this.first;\n}"))
+
+	desiredResult := `export class FullNamePerson {
+	first: string;
+	last: string;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.first = source["first"];
+		this.last = source["last"];
+	}
+
+	static readonly KIND = "person";
+
+	greet(): string {
+		return "Hello " + this.first;
+	}
+
+	get fullName(): string {
+		return this.first + " " + this.last;
+	}
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new FullNamePerson({"first": "Jane", "last": "Doe"}).fullName === "Jane Doe"`,
+		`new FullNamePerson({"first": "Jane"}).greet() === "Hello Jane"`,
+		`FullNamePerson.KIND === "person"`,
+	})
+}
+
+func (FullNamePerson) JSCustomCode() string {
+	return `get fullName() {
+	return this.first + " " + this.last;
+}`
+}
+
+func TestStructCustomCodeInterface(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithInterface(true).
+		Add(NewStruct(FullNamePerson{}).WithCustomCode("static readonly KIND = \"person\";"))
+
+	desiredResult := `export interface FullNamePerson {
+	first: string;
+	last: string;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`({"first": "Jane", "last": "Doe"} as FullNamePerson).last === "Doe"`,
+	})
+}
+
+func TestConvertToFileIfChanged(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	converter := New().WithBackupDir("").Add(Address{})
+
+	changed, err := converter.ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	original, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(original), "export class Address {")
+	info, err := os.Stat(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	changed, err = converter.ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	type Invalid struct {
+		Channel chan int `json:"channel"`
+	}
+	_, err = New().WithBackupDir("").Add(Invalid{}).ConvertToFileIfChanged(fileName)
+	assert.NotNil(t, err)
+	unchanged, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, string(original), string(unchanged))
+
+	changed, err = New().WithBackupDir("").Add(Dummy{}).ConvertToFileIfChanged(fileName)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	files, err := os.ReadDir(filepath.Dir(fileName))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files), "no temporary files left")
+}
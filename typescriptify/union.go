@@ -0,0 +1,166 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type unionMember struct {
+	value string
+	typ   reflect.Type
+}
+
+type unionType struct {
+	typ           reflect.Type
+	discriminator string
+	members       []unionMember
+}
+
+// AddUnion registers a Golang interface as a discriminated union of its implementations.
+//
+// iface is a nil pointer to the interface (i.e. `(*Shape)(nil)`), discriminator is the JSON field with the member type,
+// and members maps discriminator values to implementations:
+//
+//	converter.AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}})
+//
+// Fields of this interface type will be typed as `Circle | Square`, and (with classes) constructed with a generated
+// `createShape()` function which instantiates the class by the discriminator value.
+//
+// Invalid unions (i.e. a member which isn't a struct implementing the interface) make Convert fail.
+func (t *TypeScriptify) AddUnion(iface interface{}, discriminator string, members map[string]interface{}) *TypeScriptify {
+	if t.unions == nil {
+		t.unions = map[reflect.Type]unionType{}
+	}
+
+	var typ reflect.Type
+	if ty, is := iface.(reflect.Type); is {
+		typ = ty
+	} else {
+		typ = reflect.TypeOf(iface)
+	}
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Interface {
+		t.unionErrors = append(t.unionErrors, fmt.Errorf("union %T isn't a pointer to an interface", iface))
+		return t
+	}
+
+	union := unionType{typ: typ, discriminator: discriminator}
+	for value, member := range members {
+		memberType := reflect.TypeOf(member)
+		if memberType != nil && memberType.Kind() == reflect.Ptr {
+			memberType = memberType.Elem()
+		}
+		if memberType == nil || memberType.Kind() != reflect.Struct {
+			t.unionErrors = append(t.unionErrors, fmt.Errorf("member %q of union %s isn't a struct (%T)", value, typ.String(), member))
+			return t
+		}
+		if !memberType.Implements(typ) && !reflect.PtrTo(memberType).Implements(typ) {
+			t.unionErrors = append(t.unionErrors, fmt.Errorf("member %q of union %s: %s doesn't implement it", value, typ.String(), memberType.String()))
+			return t
+		}
+		union.members = append(union.members, unionMember{value: value, typ: memberType})
+	}
+	sort.Slice(union.members, func(i, j int) bool { return union.members[i].value < union.members[j].value })
+
+	t.unions[typ] = union
+	t.unionTypes = append(t.unionTypes, typ)
+	return t
+}
+
+func (t *TypeScriptify) unionName(union unionType) string {
+	return t.Prefix + union.typ.Name() + t.Suffix
+}
+
+func (t *TypeScriptify) unionFactoryName(union unionType) string {
+	return "create" + t.unionName(union)
+}
+
+// unionDiscriminators returns the discriminator fields (JSON name to the literal value) of a union member struct. If
+// the struct is in more unions with the same discriminator, the first registered union is used.
+func (t *TypeScriptify) unionDiscriminators(typeOf reflect.Type) map[string]string {
+	var result map[string]string
+	for _, unionTyp := range t.unionTypes {
+		union := t.unions[unionTyp]
+		for _, member := range union.members {
+			if _, found := result[union.discriminator]; member.typ != typeOf || found {
+				continue
+			}
+			if result == nil {
+				result = map[string]string{}
+			}
+			result[union.discriminator] = fmt.Sprintf("%q", member.value)
+		}
+	}
+	return result
+}
+
+// unionFieldType returns the union of a field type (also in pointers, slices and maps).
+func (t *TypeScriptify) unionFieldType(typ reflect.Type) (unionType, bool) {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		default:
+			union, found := t.unions[typ]
+			return union, found
+		}
+	}
+}
+
+func (t *TypeScriptify) convertUnion(depth int, union unionType, customCode map[string]string) (string, error) {
+	t.logf(depth, "Converting union %s", union.typ.String())
+
+	result := ""
+	var memberNames []string
+	for _, member := range union.members {
+		typeScriptChunk, err := t.convertType(depth+1, member.typ, customCode)
+		if err != nil {
+			return "", err
+		}
+		if typeScriptChunk != "" {
+			result += strings.Trim(typeScriptChunk, " "+t.Indent+"\r\n") + "\n"
+		}
+		memberNames = append(memberNames, t.Prefix+t.structName(member.typ)+t.Suffix)
+	}
+
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	unionName := t.unionName(union)
+	result += strings.TrimSuffix(t.typeAlias(unionName, strings.Join(memberNames, " | ")), "\n")
+
+	if !t.CreateInterface {
+		switch t.Output {
+		case DeclarationOutput:
+			result += fmt.Sprintf("\n%sdeclare function %s(source?: any): %s;", export, t.unionFactoryName(union), unionName)
+			return result, nil
+		case JavaScriptOutput:
+			result += "\n" + jsDoc("", "@param {any} [source]", "@returns {"+unionName+"}")
+			result += fmt.Sprintf("%sfunction %s(source = {}) {\n", export, t.unionFactoryName(union))
+		default:
+			result += fmt.Sprintf("\n%sfunction %s(source: any = {}): %s {\n", export, t.unionFactoryName(union), unionName)
+		}
+		result += t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
+		result += fmt.Sprintf("%sswitch (source?.[\"%s\"]) {\n", t.Indent, union.discriminator)
+		for n, member := range union.members {
+			if t.isInterface(member.typ) {
+				result += fmt.Sprintf("%s%scase %q: return source;\n", t.Indent, t.Indent, member.value)
+			} else if !t.CreateConstructor {
+				// Classes without constructors only get the prototype:
+				result += fmt.Sprintf("%s%scase %q: return Object.assign(new %s(), source);\n", t.Indent, t.Indent, member.value, memberNames[n])
+			} else {
+				result += fmt.Sprintf("%s%scase %q: return new %s(source);\n", t.Indent, t.Indent, member.value, memberNames[n])
+			}
+		}
+		result += t.Indent + "}\n"
+		result += t.Indent + "return source;\n"
+		result += "}"
+	}
+
+	return result, nil
+}
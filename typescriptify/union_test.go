@@ -0,0 +1,165 @@
+package typescriptify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+// Drawable is implemented by the same structs as Shape.
+type Drawable interface {
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type Drawing struct {
+	Main   Shape            `json:"main"`
+	Shapes []Shape          `json:"shapes"`
+	ByName map[string]Shape `json:"by_name"`
+}
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(Drawing{})
+
+	desiredResult := `export class Circle {
+	kind: "circle";
+	radius: number;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.kind = "circle";
+		this.radius = source["radius"];
+	}
+}
+export class Square {
+	kind: "square";
+	side: number;
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.kind = "square";
+		this.side = source["side"];
+	}
+}
+export type Shape = Circle | Square;
+export function createShape(source: any = {}): Shape {
+	if ('string' === typeof source) source = JSON.parse(source);
+	switch (source?.["kind"]) {
+		case "circle": return new Circle(source);
+		case "square": return new Square(source);
+	}
+	return source;
+}
+export class Drawing {
+	main: Shape;
+	shapes: Shape[];
+	by_name: {[key: string]: Shape};
+
+	constructor(source: any = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.main = this.convertValues(source["main"], createShape);
+		this.shapes = this.convertValues(source["shapes"], createShape);
+		this.by_name = this.convertValues(source["by_name"], createShape, true);
+	}
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := `{"main": {"kind": "square", "side": 2}, "shapes": [{"kind": "circle", "radius": 1}], "by_name": {"c": {"kind": "circle", "radius": 3}}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Drawing(` + jsn + `).main instanceof Square`,
+		`(new Drawing(` + jsn + `).main as Square).side === 2`,
+		`new Drawing(` + jsn + `).shapes[0] instanceof Circle`,
+		`new Drawing(` + jsn + `).by_name["c"] instanceof Circle`,
+		`createShape({"kind": "circle"}).kind === "circle"`,
+	})
+}
+
+func TestUnionWithoutConstructors(t *testing.T) {
+	t.Parallel()
+
+	converter := New().WithIndent("\t").WithConstructor(false).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}})
+
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, typeScriptCode, `		case "circle": return Object.assign(new Circle(), source);
+		case "square": return Object.assign(new Square(), source);`)
+	assert.NotContains(t, typeScriptCode, "new Circle(source)")
+	testTypescriptExpression(t, false, typeScriptCode, []string{
+		`createShape({"kind": "circle", "radius": 2}) instanceof Circle`,
+		`(createShape({"kind": "circle", "radius": 2}) as Circle).radius === 2`,
+	})
+}
+
+func TestUnionInterfaces(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		WithInterface(true).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": &Square{}}).
+		Add(Drawing{})
+
+	desiredResult := `export interface Circle {
+	kind: "circle";
+	radius: number;
+}
+export interface Square {
+	kind: "square";
+	side: number;
+}
+export type Shape = Circle | Square;
+export interface Drawing {
+	main: Shape;
+	shapes: Shape[];
+	by_name: {[key: string]: Shape};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestUnionInvalidMember(t *testing.T) {
+	t.Parallel()
+
+	for _, members := range []map[string]interface{}{
+		{"drawing": Drawing{}},
+		{"circle": Circle{}, "nothing": nil},
+		{"number": 1},
+	} {
+		_, err := New().AddUnion((*Shape)(nil), "kind", members).Convert(nil)
+		assert.NotNil(t, err)
+	}
+	_, err := New().AddUnion(Circle{}, "kind", map[string]interface{}{"circle": Circle{}}).Convert(nil)
+	assert.NotNil(t, err)
+}
+
+func TestUnionDiscriminatorsOrder(t *testing.T) {
+	t.Parallel()
+
+	// Circle is in two unions with the same discriminator, the first registered wins:
+	for i := 0; i < 20; i++ {
+		converter := New().
+			AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+			AddUnion((*Drawable)(nil), "kind", map[string]interface{}{"round": Circle{}})
+		assert.Equal(t, map[string]string{"kind": `"circle"`}, converter.unionDiscriminators(reflect.TypeOf(Circle{})))
+	}
+}
@@ -9,3 +9,11 @@ func indentLines(str string, i int) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+func indentLinesWith(str string, indent string) string {
+	lines := strings.Split(str, "\n")
+	for n := range lines {
+		lines[n] = indent + lines[n]
+	}
+	return strings.Join(lines, "\n")
+}